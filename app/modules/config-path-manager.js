@@ -40,7 +40,22 @@ class ConfigPathManager {
     initializeBootstrapSettings() {
         const appDir = path.join(__dirname, '..');
         this.settingsFile = path.join(appDir, '.config_path');
-        
+
+        // The launcher's --config-dir takes precedence over .config_path
+        const launcherConfigDir = process.env.LTTH_CONFIG_DIR;
+        if (launcherConfigDir) {
+            try {
+                fs.mkdirSync(launcherConfigDir, { recursive: true });
+                const testFile = path.join(launcherConfigDir, '.write_test');
+                fs.writeFileSync(testFile, 'test');
+                fs.unlinkSync(testFile);
+                this.customConfigPath = launcherConfigDir;
+                return;
+            } catch (error) {
+                console.warn(`Warning: LTTH_CONFIG_DIR not usable, falling back: ${error.message}`);
+            }
+        }
+
         // Read custom path if exists
         if (fs.existsSync(this.settingsFile)) {
             try {
@@ -366,19 +366,34 @@ class Launcher {
             });
 
             // Cleanup bei Exit
+            // Signal weiterreichen und auf das Server-Exit warten, damit der
+            // Server Datenbank und Cloud-Sync sauber schließen kann
+            let interruptCount = 0;
             process.on('SIGINT', () => {
+                interruptCount++;
+                if (interruptCount > 1) {
+                    // Zweites Strg+C: nicht länger auf den Server warten
+                    serverProcess.kill('SIGKILL');
+                    process.exit(1);
+                }
                 this.log.newLine();
                 this.log.separator();
                 this.log.info('Server wird beendet...');
                 serverProcess.kill('SIGINT');
-                process.exit(0);
             });
 
             process.on('SIGTERM', () => {
                 serverProcess.kill('SIGTERM');
-                process.exit(0);
             });
 
+            if (process.platform === 'win32') {
+                // CTRL_BREAK erreicht die ganze Prozessgruppe - der Server
+                // bekommt es selbst, hier nur nicht vorzeitig beenden
+                process.on('SIGBREAK', () => {
+                    this.log.info('Server wird beendet...');
+                });
+            }
+
             // Warte auf Server-Exit
             serverProcess.on('exit', (code) => {
                 this.log.newLine();
@@ -369,6 +369,17 @@ if (!activeProfile) {
     }
 }
 
+// Im Launcher gewähltes Profil (--profile oder Profilauswahl beim Start)
+const launcherProfile = process.env.LTTH_PROFILE;
+if (launcherProfile && /^[a-zA-Z0-9_-]+$/.test(launcherProfile) && launcherProfile !== activeProfile) {
+    if (!profileManager.profileExists(launcherProfile)) {
+        logger.info(`📝 Erstelle neues Profil: ${launcherProfile}`);
+        profileManager.createProfile(launcherProfile);
+    }
+    profileManager.setActiveProfile(launcherProfile);
+    activeProfile = launcherProfile;
+}
+
 logger.info(`👤 Aktives User-Profil: ${activeProfile}`);
 
 // ========== INITIALIZATION STATE MANAGER ==========
@@ -3336,7 +3347,14 @@ const PORT = process.env.PORT || 3000;
 })(); // Schließe async IIFE
 
 // Graceful Shutdown
-process.on('SIGINT', async () => {
+// SIGTERM comes from service managers and the launcher on Linux/macOS,
+// SIGBREAK is what the Windows launcher delivers via CTRL_BREAK
+let shuttingDown = false;
+const gracefulShutdown = async () => {
+    if (shuttingDown) {
+        return;
+    }
+    shuttingDown = true;
     logger.info('\n\n🛑 Shutting down gracefully...');
 
     // TikTok-Verbindung trennen
@@ -3364,7 +3382,13 @@ process.on('SIGINT', async () => {
         logger.info('✅ Server closed');
         process.exit(0);
     });
-});
+};
+
+process.on('SIGINT', gracefulShutdown);
+process.on('SIGTERM', gracefulShutdown);
+if (process.platform === 'win32') {
+    process.on('SIGBREAK', gracefulShutdown);
+}
 
 // Error Handling
 process.on('uncaughtException', (error) => {
@@ -19,6 +19,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/npmcache"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/procutil"
 	"github.com/pkg/browser"
 )
 
@@ -165,12 +167,8 @@ func (l *Launcher) installDependencies() error {
 	l.updateProgress(45, "HINWEIS: npm install kann mehrere Minuten dauern, besonders bei langsamer Internetverbindung. Bitte warten...")
 	time.Sleep(2 * time.Second)
 	
-	var cmd *exec.Cmd
-	if runtime.GOOS == "windows" {
-		cmd = exec.Command("cmd", "/C", "npm", "install", "--cache", "false")
-	} else {
-		cmd = exec.Command("npm", "install", "--cache", "false")
-	}
+	args := append([]string{"install"}, npmcache.Args(filepath.Join(filepath.Dir(l.appDir), npmcache.DirName))...)
+	cmd := procutil.NPM(args...)
 	
 	cmd.Dir = l.appDir
 	
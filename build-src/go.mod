@@ -4,4 +4,6 @@ go 1.24.10
 
 require github.com/pkg/browser v0.0.0-20240102092130-5ac0b6a4141c
 
-require golang.org/x/sys v0.1.0 // indirect
+require golang.org/x/sys v0.1.0
+
+require github.com/yuin/goldmark v1.7.13
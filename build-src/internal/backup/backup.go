@@ -0,0 +1,392 @@
+// Package backup snapshots the user's configuration before the launchers
+// change the app tree (update extraction, npm install, .env merge) and
+// restores such snapshots.
+//
+// A snapshot is a zip file with two roots: "app/" for files inside the app
+// directory and "config/" for the ConfigPathManager directory, plus a
+// manifest describing where they came from.
+package backup
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ManifestName is the manifest entry inside every snapshot.
+const ManifestName = "ltth-backup.json"
+
+// appName matches ConfigPathManager.APP_NAME.
+const appName = "pupcidslittletiktokhelper"
+
+// AppEntries are the paths inside the app directory that hold user data.
+var AppEntries = []string{".env", ".config_path", "user_configs", "user_data"}
+
+// Sources are the directories a snapshot is taken from and restored to.
+type Sources struct {
+	AppDir    string
+	ConfigDir string // see ConfigDir
+}
+
+// Manifest describes a snapshot.
+type Manifest struct {
+	Created   time.Time `json:"created"`
+	Reason    string    `json:"reason"`
+	AppDir    string    `json:"appDir"`
+	ConfigDir string    `json:"configDir"`
+	Files     int       `json:"files"`
+	Bytes     int64     `json:"bytes"`
+}
+
+// Info is a snapshot found by List.
+type Info struct {
+	Path string
+	Manifest
+}
+
+// ConfigDir resolves the ConfigPathManager directory the same way the Node
+// side does: the path in app/.config_path if it is an existing directory,
+// otherwise the platform default.
+func ConfigDir(appDir string) string {
+	if data, err := os.ReadFile(filepath.Join(appDir, ".config_path")); err == nil {
+		custom := strings.TrimSpace(string(data))
+		if info, err := os.Stat(custom); custom != "" && err == nil && info.IsDir() {
+			return custom
+		}
+	}
+	return DefaultConfigDir()
+}
+
+// DefaultConfigDir is the platform directory ConfigPathManager uses
+// without app/.config_path.
+func DefaultConfigDir() string {
+	home, _ := os.UserHomeDir()
+	switch runtime.GOOS {
+	case "windows":
+		base := os.Getenv("LOCALAPPDATA")
+		if base == "" {
+			base = filepath.Join(home, "AppData", "Local")
+		}
+		return filepath.Join(base, appName)
+	case "darwin":
+		return filepath.Join(home, "Library", "Application Support", appName)
+	default:
+		return filepath.Join(home, ".local", "share", appName)
+	}
+}
+
+// SetConfigDir points app/.config_path at dir. The file is replaced via a
+// temp file, so it also works when the old file is not writable itself.
+func SetConfigDir(appDir, dir string) error {
+	tmp, err := os.CreateTemp(appDir, ".config_path.tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.WriteString(dir)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	os.Chmod(tmp.Name(), 0644)
+	return os.Rename(tmp.Name(), filepath.Join(appDir, ".config_path"))
+}
+
+// Create writes a snapshot of src into dir and returns its path. Sources
+// that do not exist are skipped; if there is nothing at all to save, no
+// file is written and the path is empty.
+func Create(dir string, src Sources, reason string, now time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	stamp := now.Format("2006-01-02_15-04-05")
+	final := filepath.Join(dir, fmt.Sprintf("ltth-backup_%s_%s.zip", stamp, reason))
+	for i := 2; fileExists(final); i++ {
+		final = filepath.Join(dir, fmt.Sprintf("ltth-backup_%s_%s-%d.zip", stamp, reason, i))
+	}
+	tmp, err := os.CreateTemp(dir, ".ltth-backup-*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	m := Manifest{Created: now, Reason: reason, AppDir: src.AppDir, ConfigDir: src.ConfigDir}
+	zw := zip.NewWriter(tmp)
+	for _, entry := range AppEntries {
+		if err := addTree(zw, filepath.Join(src.AppDir, entry), path.Join("app", entry), dir, &m); err != nil {
+			tmp.Close()
+			return "", err
+		}
+	}
+	if src.ConfigDir != "" {
+		if err := addTree(zw, src.ConfigDir, "config", dir, &m); err != nil {
+			tmp.Close()
+			return "", err
+		}
+	}
+	if m.Files == 0 {
+		zw.Close()
+		tmp.Close()
+		return "", nil
+	}
+
+	w, err := zw.Create(ManifestName)
+	if err == nil {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(m)
+	}
+	if err == nil {
+		err = zw.Close()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", err
+	}
+	return final, os.Rename(tmp.Name(), final)
+}
+
+// addTree adds the file or directory at src under the zip name prefix.
+// Symlinks, special files and the backup directory itself are skipped.
+func addTree(zw *zip.Writer, src, prefix, skip string, m *Manifest) error {
+	if _, err := os.Lstat(src); os.IsNotExist(err) {
+		return nil
+	}
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && p == skip {
+			return filepath.SkipDir
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		hdr.Name = path.Join(prefix, filepath.ToSlash(rel))
+		hdr.Method = zip.Deflate
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		n, err := io.Copy(w, f)
+		f.Close()
+		m.Files++
+		m.Bytes += n
+		return err
+	})
+}
+
+// List returns the snapshots in dir, newest first. Files that are not
+// readable snapshots are ignored.
+func List(dir string) ([]Info, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "ltth-backup_*.zip"))
+	if err != nil {
+		return nil, err
+	}
+	var list []Info
+	for _, p := range matches {
+		m, err := readManifest(p)
+		if err != nil {
+			continue
+		}
+		list = append(list, Info{Path: p, Manifest: m})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Created.After(list[j].Created) })
+	return list, nil
+}
+
+// Prune deletes all but the newest keep snapshots and returns the removed
+// paths.
+func Prune(dir string, keep int) ([]string, error) {
+	list, err := List(dir)
+	if err != nil || len(list) <= keep {
+		return nil, err
+	}
+	var removed []string
+	for _, info := range list[keep:] {
+		if err := os.Remove(info.Path); err != nil {
+			return removed, err
+		}
+		removed = append(removed, info.Path)
+	}
+	return removed, nil
+}
+
+func readManifest(p string) (Manifest, error) {
+	var m Manifest
+	zr, err := zip.OpenReader(p)
+	if err != nil {
+		return m, err
+	}
+	defer zr.Close()
+	for _, f := range zr.File {
+		if f.Name != ManifestName {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return m, err
+		}
+		defer rc.Close()
+		return m, json.NewDecoder(rc).Decode(&m)
+	}
+	return m, errors.New("kein LTTH-Backup (Manifest fehlt)")
+}
+
+// Restore writes the files of the snapshot at p back into dst. Files that
+// were created after the snapshot are kept. app/.config_path is restored
+// first, so "config/" goes to the directory it points to unless
+// dst.ConfigDir is set explicitly.
+func Restore(p string, dst Sources) error {
+	zr, err := zip.OpenReader(p)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	files := append([]*zip.File(nil), zr.File...)
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].Name == "app/.config_path" && files[j].Name != "app/.config_path"
+	})
+
+	// Check every entry before anything is written
+	for _, f := range files {
+		if _, err := entryTarget(f.Name, Sources{AppDir: "app", ConfigDir: "config"}); err != nil {
+			return err
+		}
+	}
+
+	pinned := dst.ConfigDir != ""
+	restored := map[string]bool{}
+	for _, f := range files {
+		if f.Name == ManifestName || strings.HasSuffix(f.Name, "/") {
+			continue
+		}
+		if strings.HasPrefix(f.Name, "config/") && dst.ConfigDir == "" {
+			dst.ConfigDir = ConfigDir(dst.AppDir)
+		}
+		target, err := entryTarget(f.Name, dst)
+		if err != nil {
+			return err
+		}
+		if err := extract(f, target); err != nil {
+			return err
+		}
+		restored[target] = true
+		if f.Name == "app/.config_path" && !pinned {
+			dst.ConfigDir = ""
+		}
+	}
+
+	// A newer SQLite journal next to a restored database would be replayed
+	// on top of it and corrupt it
+	for target := range restored {
+		for _, suffix := range []string{"-wal", "-shm", "-journal"} {
+			if !restored[target+suffix] {
+				if err := os.Remove(target + suffix); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// entryTarget maps an archive entry to its file in dst. The manifest and
+// directory entries have no target.
+func entryTarget(name string, dst Sources) (string, error) {
+	if name == ManifestName || strings.HasSuffix(name, "/") {
+		return "", nil
+	}
+	root, rel, _ := strings.Cut(name, "/")
+	var base string
+	switch root {
+	case "app":
+		if top, _, _ := strings.Cut(rel, "/"); !contains(AppEntries, top) {
+			return "", fmt.Errorf("unerwarteter Eintrag %q", name)
+		}
+		base = dst.AppDir
+	case "config":
+		base = dst.ConfigDir
+	default:
+		return "", fmt.Errorf("unerwarteter Eintrag %q", name)
+	}
+	return safeJoin(base, rel)
+}
+
+// safeJoin joins a slash-separated archive path to base and rejects paths
+// that would end up outside of it.
+func safeJoin(base, rel string) (string, error) {
+	if rel == "" || path.IsAbs(rel) || strings.Contains(rel, `\`) {
+		return "", fmt.Errorf("ungültiger Pfad im Backup: %q", rel)
+	}
+	clean := path.Clean(rel)
+	if clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("ungültiger Pfad im Backup: %q", rel)
+	}
+	return filepath.Join(base, filepath.FromSlash(clean)), nil
+}
+
+func extract(f *zip.File, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode().Perm()|0200)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, rc); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func fileExists(p string) bool {
+	_, err := os.Stat(p)
+	return err == nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
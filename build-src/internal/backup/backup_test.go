@@ -0,0 +1,169 @@
+package backup
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func readFile(t *testing.T, p string) string {
+	t.Helper()
+	data, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestCreateRestore(t *testing.T) {
+	tmp := t.TempDir()
+	src := Sources{AppDir: filepath.Join(tmp, "app"), ConfigDir: filepath.Join(tmp, "config")}
+	writeFiles(t, src.AppDir, map[string]string{
+		".env":                             "PORT=3000\n",
+		"user_configs/default/database.db": "db v1",
+		"user_data/sounds/hi.mp3":          "mp3",
+		"server.js":                        "not user data",
+	})
+	writeFiles(t, src.ConfigDir, map[string]string{
+		"settings.json": `{"theme":"dark"}`,
+	})
+	dir := filepath.Join(tmp, "backups")
+	now := time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	p, err := Create(dir, src, "npm-install", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "ltth-backup_2025-03-01_12-30-00_npm-install.zip"); p != want {
+		t.Errorf("Create() = %s, want %s", p, want)
+	}
+	list, err := List(dir)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("List() = %v, %v", list, err)
+	}
+	if m := list[0].Manifest; m.Files != 4 || m.Reason != "npm-install" || !m.Created.Equal(now) {
+		t.Errorf("manifest %+v", m)
+	}
+
+	// Change everything after the snapshot
+	writeFiles(t, src.AppDir, map[string]string{
+		".env":                                 "PORT=4000\n",
+		"user_configs/default/database.db":     "db v2",
+		"user_configs/default/database.db-wal": "journal of v2",
+		"user_configs/new/database.db":         "created later",
+	})
+	os.Remove(filepath.Join(src.AppDir, "user_data", "sounds", "hi.mp3"))
+	writeFiles(t, src.ConfigDir, map[string]string{"settings.json": "{}"})
+
+	if err := Restore(p, src); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"app/.env":                             "PORT=3000\n",
+		"app/user_configs/default/database.db": "db v1",
+		"app/user_data/sounds/hi.mp3":          "mp3",
+		"app/user_configs/new/database.db":     "created later",
+		"config/settings.json":                 `{"theme":"dark"}`,
+	} {
+		if got := readFile(t, filepath.Join(tmp, filepath.FromSlash(name))); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(src.AppDir, "user_configs", "default", "database.db-wal")); !os.IsNotExist(err) {
+		t.Errorf("stale -wal next to the restored database was kept: %v", err)
+	}
+}
+
+func TestCreateNothingToSave(t *testing.T) {
+	tmp := t.TempDir()
+	dir := filepath.Join(tmp, "backups")
+	p, err := Create(dir, Sources{AppDir: filepath.Join(tmp, "app")}, "update", time.Now())
+	if err != nil || p != "" {
+		t.Fatalf("Create() = %q, %v, want no snapshot", p, err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("left %d files in the backup directory", len(entries))
+	}
+}
+
+func TestRestoreRejectsForeignEntries(t *testing.T) {
+	tmp := t.TempDir()
+	for _, name := range []string{"app/../evil", "app/server.js", "config/../../evil", "other/file"} {
+		p := filepath.Join(tmp, "crafted.zip")
+		f, err := os.Create(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		zw := zip.NewWriter(f)
+		w, _ := zw.Create(name)
+		w.Write([]byte("x"))
+		zw.Close()
+		f.Close()
+
+		dst := Sources{AppDir: filepath.Join(tmp, "app"), ConfigDir: filepath.Join(tmp, "config")}
+		if err := Restore(p, dst); err == nil {
+			t.Errorf("Restore accepted entry %q", name)
+		}
+		if _, err := os.Stat(filepath.Join(tmp, "evil")); !os.IsNotExist(err) {
+			t.Errorf("entry %q was written outside the target", name)
+		}
+	}
+}
+
+func TestPrune(t *testing.T) {
+	tmp := t.TempDir()
+	src := Sources{AppDir: filepath.Join(tmp, "app")}
+	writeFiles(t, src.AppDir, map[string]string{".env": "PORT=3000\n"})
+	dir := filepath.Join(tmp, "backups")
+
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	var created []string
+	for i := 0; i < 5; i++ {
+		p, err := Create(dir, src, "update", start.Add(time.Duration(i)*time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		created = append(created, p)
+	}
+	// Not a snapshot, must survive
+	writeFiles(t, dir, map[string]string{"notes.txt": "keep me"})
+
+	removed, err := Prune(dir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 3 {
+		t.Fatalf("Prune removed %v, want the 3 oldest", removed)
+	}
+	for i, p := range created {
+		_, err := os.Stat(p)
+		if kept := i >= 3; kept != (err == nil) {
+			t.Errorf("snapshot %d: kept = %v, want %v", i, err == nil, kept)
+		}
+	}
+	if readFile(t, filepath.Join(dir, "notes.txt")) != "keep me" {
+		t.Errorf("Prune touched a foreign file")
+	}
+
+	if removed, err := Prune(dir, 2); err != nil || len(removed) != 0 {
+		t.Errorf("second Prune() = %v, %v, want nothing to remove", removed, err)
+	}
+	if removed, err := Prune(dir, 0); err != nil || len(removed) != 2 {
+		t.Errorf("Prune(0) = %v, %v, want both removed", removed, err)
+	}
+}
@@ -0,0 +1,183 @@
+// Package changelog renders app/CHANGELOG.md (Keep a Changelog format) for
+// the launcher's splash page and tells which versions are new to the user.
+package changelog
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/semver"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// VersionFile in the config directory holds the app version of the last
+// successful start.
+const VersionFile = ".launcher_last_version"
+
+// md is a CommonMark renderer without the unsafe option: raw HTML is
+// omitted and javascript:, vbscript:, file: and data: links lose their href.
+var md = goldmark.New(
+	goldmark.WithParserOptions(parser.WithASTTransformers(util.Prioritized(linkTransformer{}, 100))),
+)
+
+// headingPattern splits "## [1.2.1] - 2025-12-09" into version and title.
+var headingPattern = regexp.MustCompile(`^\[?([^\]\s]+)\]?(?:\s+-\s+(.*))?$`)
+
+// Section is one "## " version section of the changelog.
+type Section struct {
+	Version  string `json:"version"` // "1.2.1" or "Unreleased"
+	Title    string `json:"title"`   // rest of the heading, a date or a name
+	Markdown string `json:"markdown"`
+	HTML     string `json:"html"` // rendered body, without the heading
+	New      bool   `json:"new"`  // released after the version the user last started
+}
+
+// ToHTML renders markdown as sanitized HTML. Links open in a new tab so
+// the splash page keeps running.
+func ToHTML(markdown []byte) (string, error) {
+	var buf bytes.Buffer
+	if err := md.Convert(markdown, &buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Parse splits the changelog into its version sections, in file order. The
+// title and introduction above the first section are dropped.
+func Parse(markdown []byte) ([]Section, error) {
+	var sections []Section
+	var body strings.Builder
+	flush := func() error {
+		if len(sections) == 0 {
+			return nil
+		}
+		s := &sections[len(sections)-1]
+		s.Markdown = strings.TrimSpace(body.String())
+		rendered, err := ToHTML([]byte(s.Markdown))
+		s.HTML = rendered
+		body.Reset()
+		return err
+	}
+
+	fence := ""
+	for _, line := range strings.SplitAfter(string(markdown), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+		case strings.HasPrefix(line, "## "):
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			heading := strings.TrimSpace(strings.TrimPrefix(line, "## "))
+			s := Section{Version: heading}
+			if m := headingPattern.FindStringSubmatch(heading); m != nil {
+				s.Version, s.Title = m[1], m[2]
+			}
+			sections = append(sections, s)
+			continue
+		}
+		if len(sections) > 0 {
+			body.WriteString(line)
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return sections, nil
+}
+
+// MarkNew flags the sections released after last, up to and including
+// current. Without a last version (first start) only current is new.
+// "Unreleased" and sections without a SemVer heading are never new.
+func MarkNew(sections []Section, last, current string) {
+	cur, err := semver.Parse(current)
+	if err != nil {
+		return
+	}
+	prev, err := semver.Parse(last)
+	for i := range sections {
+		v, verr := semver.Parse(sections[i].Version)
+		if verr != nil || cur.Less(v) {
+			continue
+		}
+		if err != nil {
+			sections[i].New = v.Compare(cur) == 0
+		} else {
+			sections[i].New = prev.Less(v)
+		}
+	}
+}
+
+// AppVersion returns the "version" of appDir/package.json, "" if unknown.
+func AppVersion(appDir string) string {
+	data, err := os.ReadFile(filepath.Join(appDir, "package.json"))
+	if err != nil {
+		return ""
+	}
+	var pkg struct {
+		Version string `json:"version"`
+	}
+	json.Unmarshal(data, &pkg)
+	return pkg.Version
+}
+
+// LastVersion returns the version recorded by RecordVersion, "" if none.
+func LastVersion(configDir string) string {
+	data, err := os.ReadFile(filepath.Join(configDir, VersionFile))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// RecordVersion remembers version as the last one the user started.
+func RecordVersion(configDir, version string) error {
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(configDir, VersionFile), []byte(version+"\n"), 0644)
+}
+
+type linkTransformer struct{}
+
+// Transform adds target and rel to every link. The HTML renderer does not
+// check autolinks, so "<javascript:...>" is turned back into plain text.
+func (linkTransformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	var unsafe []*ast.AutoLink
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch link := n.(type) {
+		case *ast.AutoLink:
+			if link.AutoLinkType == ast.AutoLinkURL && html.IsDangerousURL(link.URL(source)) {
+				unsafe = append(unsafe, link)
+				return ast.WalkSkipChildren, nil
+			}
+		case *ast.Link:
+		default:
+			return ast.WalkContinue, nil
+		}
+		n.SetAttributeString("target", "_blank")
+		n.SetAttributeString("rel", "noopener noreferrer")
+		return ast.WalkContinue, nil
+	})
+	for _, link := range unsafe {
+		link.Parent().ReplaceChild(link.Parent(), link, ast.NewString(link.Label(source)))
+	}
+}
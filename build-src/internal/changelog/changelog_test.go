@@ -0,0 +1,168 @@
+package changelog
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// TestParseGolden renders a copy of app/CHANGELOG.md. Refresh both files
+// with: cp ../../../app/CHANGELOG.md testdata/ && go test -update
+func TestParseGolden(t *testing.T) {
+	markdown, err := os.ReadFile(filepath.Join("testdata", "CHANGELOG.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sections, err := Parse(markdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var versions []string
+	for _, s := range sections {
+		versions = append(versions, s.Version)
+	}
+	if got := strings.Join(versions, " "); got != "1.2.1 Unreleased 1.0.3 1.0.2 0.9.0 0.8.0 0.6.0 0.5.0 0.4.0 0.3.0 0.2.0 0.1.0 Version Format" {
+		t.Errorf("versions = %s", got)
+	}
+	if sections[4].Title != "VDO.Ninja Multi-Guest Integration" {
+		t.Errorf("0.9.0 title = %q", sections[4].Title)
+	}
+
+	var b strings.Builder
+	for _, s := range sections {
+		fmt.Fprintf(&b, "<!-- version=%q title=%q -->\n%s\n", s.Version, s.Title, s.HTML)
+	}
+	got := b.String()
+
+	golden := filepath.Join("testdata", "CHANGELOG.golden.html")
+	if *update {
+		if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("rendered changelog differs from %s, run go test -update and review the diff", golden)
+	}
+}
+
+func TestToHTMLSanitizes(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     []string
+		forbid   []string
+	}{
+		{
+			name:     "raw script block",
+			markdown: "Fix\n\n<script>alert(1)</script>\n",
+			want:     []string{"<p>Fix</p>", "<!-- raw HTML omitted -->"},
+			forbid:   []string{"<script"},
+		},
+		{
+			name:     "inline script",
+			markdown: "Fix <script>alert(1)</script> done",
+			forbid:   []string{"<script"},
+		},
+		{
+			name:     "event handler",
+			markdown: `<img src=x onerror="alert(1)">`,
+			forbid:   []string{"onerror", "<img"},
+		},
+		{
+			name:     "javascript link",
+			markdown: "[Klick](javascript:alert(1))",
+			want:     []string{">Klick</a>"},
+			forbid:   []string{"javascript:"},
+		},
+		{
+			name:     "javascript link with mixed case",
+			markdown: "[Klick](JavaScript:alert(1))",
+			forbid:   []string{"JavaScript:", `href="JavaScript`},
+		},
+		{
+			name:     "javascript autolink",
+			markdown: "<javascript:alert(1)>",
+			want:     []string{"<p>javascript:alert(1)</p>"},
+			forbid:   []string{"<a"},
+		},
+		{
+			name:     "data link",
+			markdown: "[Bild](data:text/html;base64,PHNjcmlwdD4=)",
+			forbid:   []string{"data:text/html"},
+		},
+		{
+			name:     "https link opens in a new tab",
+			markdown: "[Release](https://github.com/Loggableim/pupcidslittletiktokhelper/releases)",
+			want:     []string{`href="https://github.com/Loggableim/pupcidslittletiktokhelper/releases"`, `target="_blank"`, `rel="noopener noreferrer"`},
+		},
+		{
+			name:     "code keeps angle brackets",
+			markdown: "`!scene <name>`",
+			want:     []string{"<code>!scene &lt;name&gt;</code>"},
+		},
+	}
+	for _, tt := range tests {
+		got, err := ToHTML([]byte(tt.markdown))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		for _, s := range tt.want {
+			if !strings.Contains(got, s) {
+				t.Errorf("%s: %q lacks %q", tt.name, got, s)
+			}
+		}
+		for _, s := range tt.forbid {
+			if strings.Contains(got, s) {
+				t.Errorf("%s: %q contains %q", tt.name, got, s)
+			}
+		}
+	}
+}
+
+func TestParseSkipsHeadingsInCode(t *testing.T) {
+	sections, err := Parse([]byte("# Changelog\n\nIntro\n\n## [1.1.0] - 2025-01-02\n\n```md\n## not a version\n```\n\n## 1.0.0\n- first\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sections) != 2 || sections[0].Version != "1.1.0" || sections[0].Title != "2025-01-02" || sections[1].Version != "1.0.0" {
+		t.Fatalf("Parse() = %+v", sections)
+	}
+	if !strings.Contains(sections[0].Markdown, "## not a version") {
+		t.Errorf("fenced heading missing from the section body: %q", sections[0].Markdown)
+	}
+}
+
+func TestMarkNew(t *testing.T) {
+	tests := []struct {
+		last, current string
+		want          string // versions marked new
+	}{
+		{"1.0.0", "1.2.0", "1.2.0 1.1.0"},
+		{"", "1.1.0", "1.1.0"},
+		{"1.2.0", "1.2.0", ""},
+		{"1.1.0", "1.1.0-beta.1", ""},
+		{"1.0.0", "not-a-version", ""},
+	}
+	for _, tt := range tests {
+		sections := []Section{{Version: "Unreleased"}, {Version: "1.2.0"}, {Version: "1.1.0"}, {Version: "1.0.0"}}
+		MarkNew(sections, tt.last, tt.current)
+		var got []string
+		for _, s := range sections {
+			if s.New {
+				got = append(got, s.Version)
+			}
+		}
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("MarkNew(%q, %q) = %v, want %s", tt.last, tt.current, got, tt.want)
+		}
+	}
+}
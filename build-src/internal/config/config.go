@@ -0,0 +1,298 @@
+// Package config holds the launcher settings. Values come from the built-in
+// defaults, are overridden by launcher.json next to the executable and
+// finally by command-line flags.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/profiles"
+)
+
+// FileName is the name of the config file next to the launcher executable.
+const FileName = "launcher.json"
+
+// Config is the complete launcher configuration.
+type Config struct {
+	AppDir            string            `json:"appDir"`            // relative paths are resolved against the executable directory
+	NodePath          string            `json:"nodePath"`          // empty: look up node in PATH
+	ServerPort        int               `json:"serverPort"`        // port the Node.js server should listen on
+	SplashPort        int               `json:"splashPort"`        // port of the launcher's own splash server
+	HealthTimeout     Duration          `json:"healthTimeout"`     // how long to wait for the first health response
+	CloseDelay        Duration          `json:"closeDelay"`        // how long error messages stay visible before exiting
+	ShutdownTimeout   Duration          `json:"shutdownTimeout"`   // grace period before the server is killed
+	LiveCheckInterval Duration          `json:"liveCheckInterval"` // how often a deferred restart checks whether the stream ended
+	WatchdogInterval  Duration          `json:"watchdogInterval"`  // health probe interval after startup, 0 disables the watchdog
+	WatchdogFailures  int               `json:"watchdogFailures"`  // bad probes in a row before the server is restarted
+	WatchdogLatency   Duration          `json:"watchdogLatency"`   // slower answers count as bad probes
+	WatchdogRestarts  int               `json:"watchdogRestarts"`  // automatic restarts per hour before the launcher gives up and exits
+	NpmArgs           []string          `json:"npmArgs"`           // extra arguments for npm install
+	NpmCache          string            `json:"npmCache"`          // npm cache of the installation, relative paths are resolved against the executable directory, empty: npm's own cache
+	Env               map[string]string `json:"env"`               // extra environment variables for the server
+	OpenBrowser       bool              `json:"openBrowser"`       // open the splash page / dashboard automatically
+	Headless          bool              `json:"headless"`          // service mode: no browser, log to stdout, fail instead of falling back
+	BackupDir         string            `json:"backupDir"`         // configuration snapshots, relative paths are resolved against the executable directory
+	BackupKeep        int               `json:"backupKeep"`        // number of snapshots to keep, 0 disables backups
+	Profile           string            `json:"profile"`           // streamer profile to start with, empty: last used / picker
+	ConfigDir         string            `json:"configDir"`         // overrides app/.config_path, relative paths are resolved against the executable directory
+	ProfileTimeout    Duration          `json:"profileTimeout"`    // how long the splash page offers the profile picker, 0 disables it
+	SelfUpdate        bool              `json:"selfUpdate"`        // download newer launcher builds and install them on the next start
+	UpdateURL         string            `json:"updateUrl"`         // signed launcher update manifest
+	Fast              bool              `json:"fast"`              // skip the pauses that keep status messages readable on the splash page
+}
+
+// Default returns the values the launcher used before it became configurable.
+func Default() Config {
+	return Config{
+		AppDir:            "app",
+		ServerPort:        3000,
+		SplashPort:        58734,
+		HealthTimeout:     Duration(60 * time.Second),
+		CloseDelay:        Duration(15 * time.Second),
+		ShutdownTimeout:   Duration(10 * time.Second),
+		LiveCheckInterval: Duration(30 * time.Second),
+		WatchdogInterval:  Duration(15 * time.Second),
+		WatchdogFailures:  4,
+		WatchdogLatency:   Duration(5 * time.Second),
+		WatchdogRestarts:  5,
+		NpmCache:          "npm-cache",
+		Env:               map[string]string{},
+		OpenBrowser:       true,
+		BackupDir:         "backups",
+		BackupKeep:        10,
+		ProfileTimeout:    Duration(15 * time.Second),
+		SelfUpdate:        true,
+		UpdateURL:         "https://github.com/Loggableim/pupcidslittletiktoolhelper_desktop/releases/latest/download/launcher-manifest.json",
+	}
+}
+
+// Load returns the defaults overlaid with exeDir/launcher.json. A missing
+// file is not an error; unknown keys are, so typos do not go unnoticed.
+func Load(exeDir string) (Config, error) {
+	cfg := Default()
+	path := filepath.Join(exeDir, FileName)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return Default(), fmt.Errorf("%s: %v", path, err)
+	}
+	if cfg.Env == nil {
+		cfg.Env = map[string]string{}
+	}
+	return cfg, cfg.Validate()
+}
+
+// Validate checks value ranges.
+func (c *Config) Validate() error {
+	if c.ServerPort < 1 || c.ServerPort > 65535 {
+		return fmt.Errorf("serverPort %d ist kein gültiger Port", c.ServerPort)
+	}
+	if c.SplashPort < 1 || c.SplashPort > 65535 {
+		return fmt.Errorf("splashPort %d ist kein gültiger Port", c.SplashPort)
+	}
+	if c.HealthTimeout <= 0 {
+		return fmt.Errorf("healthTimeout muss größer als 0 sein")
+	}
+	if c.CloseDelay < 0 {
+		return fmt.Errorf("closeDelay darf nicht negativ sein")
+	}
+	if c.ShutdownTimeout < 0 {
+		return fmt.Errorf("shutdownTimeout darf nicht negativ sein")
+	}
+	if c.LiveCheckInterval <= 0 {
+		return fmt.Errorf("liveCheckInterval muss größer als 0 sein")
+	}
+	if c.WatchdogInterval < 0 {
+		return fmt.Errorf("watchdogInterval darf nicht negativ sein")
+	}
+	if c.WatchdogFailures < 1 {
+		return fmt.Errorf("watchdogFailures muss mindestens 1 sein")
+	}
+	if c.WatchdogLatency <= 0 {
+		return fmt.Errorf("watchdogLatency muss größer als 0 sein")
+	}
+	if c.WatchdogRestarts < 0 {
+		return fmt.Errorf("watchdogRestarts darf nicht negativ sein")
+	}
+	if c.BackupKeep < 0 {
+		return fmt.Errorf("backupKeep darf nicht negativ sein")
+	}
+	if c.Profile != "" && !profiles.ValidName(c.Profile) {
+		return fmt.Errorf("profile %q ist kein gültiger Profilname (erlaubt: a-z, A-Z, 0-9, _ und -)", c.Profile)
+	}
+	if c.ProfileTimeout < 0 {
+		return fmt.Errorf("profileTimeout darf nicht negativ sein")
+	}
+	if c.SelfUpdate && !strings.HasPrefix(c.UpdateURL, "https://") && !strings.HasPrefix(c.UpdateURL, "http://") {
+		return fmt.Errorf("updateUrl %q ist keine http(s)-Adresse", c.UpdateURL)
+	}
+	return nil
+}
+
+// RegisterFlags adds a flag for every setting. The current values become
+// the flag defaults, so flags override whatever launcher.json contained.
+func (c *Config) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.AppDir, "app-dir", c.AppDir, "App-Verzeichnis (relativ zum Launcher oder absolut)")
+	fs.StringVar(&c.NodePath, "node", c.NodePath, "Pfad zur node-Executable (leer: aus PATH)")
+	fs.IntVar(&c.ServerPort, "port", c.ServerPort, "Port des Node.js Servers")
+	fs.IntVar(&c.SplashPort, "splash-port", c.SplashPort, "Port der Launcher-Startseite")
+	fs.Var(&c.HealthTimeout, "health-timeout", "Maximale Wartezeit auf den Server-Start")
+	fs.Var(&c.CloseDelay, "close-delay", "Anzeigedauer von Fehlermeldungen vor dem Beenden")
+	fs.Var(&c.ShutdownTimeout, "shutdown-timeout", "Wartezeit für das saubere Beenden des Servers, bevor er hart beendet wird")
+	fs.Var(&c.LiveCheckInterval, "live-check-interval", "Prüfintervall für Neustarts, die auf das Ende eines Live-Streams warten")
+	fs.Var(&c.WatchdogInterval, "watchdog-interval", "Intervall der Erreichbarkeitsprüfung nach dem Start (0 = aus)")
+	fs.IntVar(&c.WatchdogFailures, "watchdog-failures", c.WatchdogFailures, "Fehlgeschlagene Prüfungen in Folge bis zum Neustart")
+	fs.Var(&c.WatchdogLatency, "watchdog-latency", "Langsamere Antworten zählen als fehlgeschlagene Prüfung")
+	fs.IntVar(&c.WatchdogRestarts, "watchdog-restarts", c.WatchdogRestarts, "Automatische Neustarts pro Stunde, danach beendet sich der Launcher")
+	fs.Var((*argsValue)(&c.NpmArgs), "npm-args", "Zusätzliche Argumente für npm install (durch Leerzeichen getrennt)")
+	fs.StringVar(&c.NpmCache, "npm-cache", c.NpmCache, "npm-Cache der Installation (relativ zum Launcher oder absolut, leer = Cache von npm)")
+	fs.Var((*envValue)(&c.Env), "env", "Zusätzliche Umgebungsvariable für den Server als KEY=VALUE (mehrfach möglich)")
+	fs.BoolVar(&c.OpenBrowser, "open-browser", c.OpenBrowser, "Browser automatisch öffnen")
+	fs.StringVar(&c.BackupDir, "backup-dir", c.BackupDir, "Verzeichnis für Konfigurations-Backups (relativ zum Launcher oder absolut)")
+	fs.IntVar(&c.BackupKeep, "backup-keep", c.BackupKeep, "Anzahl aufbewahrter Backups (0 = keine Backups)")
+	fs.StringVar(&c.Profile, "profile", c.Profile, "Mit diesem Streamer-Profil starten (wird bei Bedarf angelegt)")
+	fs.StringVar(&c.ConfigDir, "config-dir", c.ConfigDir, "Konfigurationsverzeichnis statt app/.config_path (relativ zum Launcher oder absolut)")
+	fs.Var(&c.ProfileTimeout, "profile-timeout", "Anzeigedauer der Profilauswahl beim Start (0 = aus)")
+	fs.BoolVar(&c.SelfUpdate, "self-update", c.SelfUpdate, "Neuere Launcher-Versionen herunterladen und beim nächsten Start installieren")
+	fs.StringVar(&c.UpdateURL, "update-url", c.UpdateURL, "Adresse des signierten Update-Manifests für den Launcher")
+	fs.BoolVar(&c.Fast, "fast", c.Fast, "Ohne Anzeigepausen zwischen den Startphasen starten")
+	fs.BoolVar(&c.Headless, "headless", c.Headless, "Ohne Browser als Dienst laufen, Log auf stdout (journald-Format unter systemd)")
+}
+
+// ResolveAppDir returns AppDir as an absolute path.
+func (c *Config) ResolveAppDir(exeDir string) string {
+	if filepath.IsAbs(c.AppDir) {
+		return filepath.Clean(c.AppDir)
+	}
+	return filepath.Join(exeDir, c.AppDir)
+}
+
+// ResolveBackupDir returns BackupDir as an absolute path.
+func (c *Config) ResolveBackupDir(exeDir string) string {
+	if filepath.IsAbs(c.BackupDir) {
+		return filepath.Clean(c.BackupDir)
+	}
+	return filepath.Join(exeDir, c.BackupDir)
+}
+
+// ResolveNpmCache returns NpmCache as an absolute path, or "" if npm's own
+// cache should be used.
+func (c *Config) ResolveNpmCache(exeDir string) string {
+	if c.NpmCache == "" {
+		return ""
+	}
+	if filepath.IsAbs(c.NpmCache) {
+		return filepath.Clean(c.NpmCache)
+	}
+	return filepath.Join(exeDir, c.NpmCache)
+}
+
+// ResolveConfigDir returns ConfigDir as an absolute path, or "" if the
+// directory from app/.config_path should be used.
+func (c *Config) ResolveConfigDir(exeDir string) string {
+	if c.ConfigDir == "" {
+		return ""
+	}
+	if filepath.IsAbs(c.ConfigDir) {
+		return filepath.Clean(c.ConfigDir)
+	}
+	return filepath.Join(exeDir, c.ConfigDir)
+}
+
+// EnvList returns Env as sorted KEY=VALUE pairs for exec.Cmd.Env.
+func (c *Config) EnvList() []string {
+	list := make([]string, 0, len(c.Env))
+	for k, v := range c.Env {
+		list = append(list, k+"="+v)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// Duration is a time.Duration that reads "60s"-style strings or plain
+// seconds from JSON and flags.
+type Duration time.Duration
+
+// D returns the value as time.Duration.
+func (d Duration) D() time.Duration { return time.Duration(d) }
+
+func (d Duration) String() string { return time.Duration(d).String() }
+
+func (d *Duration) Set(s string) error {
+	if secs, err := strconv.ParseFloat(s, 64); err == nil {
+		*d = Duration(secs * float64(time.Second))
+		return nil
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("ungültige Dauer %q (z.B. 60s, 2m)", s)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return d.Set(s)
+	}
+	var secs float64
+	if err := json.Unmarshal(data, &secs); err != nil {
+		return fmt.Errorf("ungültige Dauer %s", data)
+	}
+	*d = Duration(secs * float64(time.Second))
+	return nil
+}
+
+type argsValue []string
+
+func (a *argsValue) String() string { return strings.Join(*a, " ") }
+
+func (a *argsValue) Set(s string) error {
+	*a = strings.Fields(s)
+	return nil
+}
+
+type envValue map[string]string
+
+func (e *envValue) String() string {
+	if e == nil {
+		return ""
+	}
+	c := Config{Env: *e}
+	return strings.Join(c.EnvList(), ",")
+}
+
+func (e *envValue) Set(s string) error {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("erwartet KEY=VALUE, bekommen %q", s)
+	}
+	if *e == nil {
+		*e = map[string]string{}
+	}
+	(*e)[key] = value
+	return nil
+}
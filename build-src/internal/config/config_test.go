@@ -0,0 +1,28 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateDurations(t *testing.T) {
+	tests := []struct {
+		name string
+		set  func(*Config)
+		ok   bool
+	}{
+		{"defaults", func(*Config) {}, true},
+		{"no close delay", func(c *Config) { c.CloseDelay = 0 }, true},
+		{"negative close delay", func(c *Config) { c.CloseDelay = Duration(-time.Second) }, false},
+		{"immediate kill", func(c *Config) { c.ShutdownTimeout = 0 }, true},
+		{"negative shutdown timeout", func(c *Config) { c.ShutdownTimeout = Duration(-time.Second) }, false},
+		{"zero health timeout", func(c *Config) { c.HealthTimeout = 0 }, false},
+	}
+	for _, tt := range tests {
+		cfg := Default()
+		tt.set(&cfg)
+		if err := cfg.Validate(); (err == nil) != tt.ok {
+			t.Errorf("%s: Validate() = %v, want ok=%v", tt.name, err, tt.ok)
+		}
+	}
+}
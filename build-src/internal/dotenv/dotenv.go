@@ -0,0 +1,222 @@
+// Package dotenv reads and writes .env files the way the Node dotenv package
+// understands them, while keeping comments, blank lines and ordering intact
+// so the launcher can edit a user's file without reformatting it.
+package dotenv
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Line is a single line of a .env file.
+type Line struct {
+	Raw       string // original text, written back unchanged unless the line is edited
+	Key       string // variable name, empty for comments and blank lines
+	Value     string // unquoted value
+	Commented bool   // "# KEY=value" - a documented default that is not active
+}
+
+// File is a parsed .env file.
+type File struct {
+	Lines []*Line
+	crlf  bool // written with Windows line endings, kept when saving
+}
+
+var assignRe = regexp.MustCompile(`^\s*(?:export\s+)?([A-Za-z_][A-Za-z0-9_]*)\s*=(.*)$`)
+
+// Parse splits data into lines and recognises active and commented-out
+// assignments.
+func Parse(data []byte) *File {
+	f := &File{crlf: strings.Contains(string(data), "\r\n")}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return f
+	}
+	for _, raw := range strings.Split(text, "\n") {
+		f.Lines = append(f.Lines, parseLine(raw))
+	}
+	return f
+}
+
+func parseLine(raw string) *Line {
+	l := &Line{Raw: raw}
+	body := strings.TrimSpace(raw)
+	if strings.HasPrefix(body, "#") {
+		body = strings.TrimSpace(strings.TrimPrefix(body, "#"))
+		if m := assignRe.FindStringSubmatch(body); m != nil && isEnvName(m[1]) {
+			l.Key, l.Value, l.Commented = m[1], unquote(m[2]), true
+		}
+		return l
+	}
+	if m := assignRe.FindStringSubmatch(body); m != nil {
+		l.Key, l.Value = m[1], unquote(m[2])
+	}
+	return l
+}
+
+// isEnvName filters prose like "# Note: x=y" from commented assignments.
+func isEnvName(key string) bool {
+	return strings.ToUpper(key) == key && strings.ContainsAny(key, "ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+}
+
+// unquote mirrors dotenv: quoted values keep everything between the quotes,
+// unquoted values end at an inline " #" comment.
+func unquote(v string) string {
+	v = strings.TrimSpace(v)
+	if len(v) >= 2 {
+		q := v[0]
+		if (q == '"' || q == '\'' || q == '`') && strings.LastIndexByte(v, q) > 0 {
+			inner := v[1:strings.LastIndexByte(v, q)]
+			if q == '"' {
+				inner = strings.ReplaceAll(inner, `\n`, "\n")
+				inner = strings.ReplaceAll(inner, `\r`, "\r")
+			}
+			return inner
+		}
+	}
+	if i := strings.Index(v, " #"); i >= 0 {
+		v = v[:i]
+	}
+	return strings.TrimSpace(v)
+}
+
+// Get returns the value of an active assignment. Like dotenv, the first
+// assignment of a key wins.
+func (f *File) Get(key string) (string, bool) {
+	if l := f.active(key); l != nil {
+		return l.Value, true
+	}
+	return "", false
+}
+
+// Has reports whether key appears at all, active or commented out.
+func (f *File) Has(key string) bool {
+	for _, l := range f.Lines {
+		if l.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Keys returns all active keys in file order.
+func (f *File) Keys() []string {
+	var keys []string
+	seen := map[string]bool{}
+	for _, l := range f.Lines {
+		if l.Key != "" && !l.Commented && !seen[l.Key] {
+			seen[l.Key] = true
+			keys = append(keys, l.Key)
+		}
+	}
+	return keys
+}
+
+// Set updates an active assignment in place, activates a commented-out one,
+// or appends a new line. Values dotenv cannot read back are refused and
+// leave the file unchanged.
+func (f *File) Set(key, value string) error {
+	raw, err := format(key, value)
+	if err != nil {
+		return err
+	}
+	if l := f.active(key); l != nil {
+		l.Value, l.Raw = value, raw
+		return nil
+	}
+	for _, l := range f.Lines {
+		if l.Key == key && l.Commented {
+			l.Value, l.Raw, l.Commented = value, raw, false
+			return nil
+		}
+	}
+	f.Lines = append(f.Lines, &Line{Raw: raw, Key: key, Value: value})
+	return nil
+}
+
+// Bytes renders the file with a trailing newline, in the line endings it
+// was read with.
+func (f *File) Bytes() []byte {
+	eol := "\n"
+	if f.crlf {
+		eol = "\r\n"
+	}
+	var b strings.Builder
+	for _, l := range f.Lines {
+		b.WriteString(l.Raw)
+		b.WriteString(eol)
+	}
+	return []byte(b.String())
+}
+
+func (f *File) active(key string) *Line {
+	for _, l := range f.Lines {
+		if l.Key == key && !l.Commented {
+			return l
+		}
+	}
+	return nil
+}
+
+// format quotes values that dotenv would otherwise cut short or trim.
+// dotenv has no escapes except \n and \r inside double quotes, so the value
+// goes into the first kind of quotes it does not contain.
+func format(key, value string) (string, error) {
+	switch {
+	case value == "" || !strings.ContainsAny(value, " #\"'`\r\n\t"):
+		return key + "=" + value, nil
+	case !strings.ContainsAny(value, "'\r\n"):
+		return key + "='" + value + "'", nil
+	case !strings.ContainsAny(value, `"\`):
+		escaped := strings.ReplaceAll(value, "\n", `\n`)
+		escaped = strings.ReplaceAll(escaped, "\r", `\r`)
+		return key + `="` + escaped + `"`, nil
+	case !strings.ContainsAny(value, "`\r\n"):
+		return key + "=`" + value + "`", nil
+	}
+	return "", fmt.Errorf("%s: Wert lässt sich in .env nicht speichern (enthält ', \" und ` oder einen Zeilenumbruch)", key)
+}
+
+// ReadFile parses the file at path.
+func ReadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data), nil
+}
+
+// WriteFile replaces path atomically: the new content goes to a temp file in
+// the same directory which is then renamed over the original. The previous
+// version is kept as path.bak.
+func WriteFile(path string, f *File) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(f.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if old, err := os.ReadFile(path); err == nil {
+		if err := os.WriteFile(path+".bak", old, 0600); err != nil {
+			return fmt.Errorf("cannot write backup: %v", err)
+		}
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,190 @@
+package dotenv
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var mergeDate = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+const mergeHeader = "# --- Neu in dieser Version (vom Launcher ergänzt am 2025-03-01) ---"
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name                string
+		base, example, user string // base "" means unknown
+		want                string
+		added, updated      []string
+	}{
+		{
+			name:    "missing key added with its comment",
+			base:    "PORT=3000\n",
+			example: "PORT=3000\n\n# Log level\n# LOG_LEVEL=info\n",
+			user:    "PORT=3001\n",
+			want:    "PORT=3001\n\n" + mergeHeader + "\n# Log level\n# LOG_LEVEL=info\n",
+			added:   []string{"LOG_LEVEL"},
+		},
+		{
+			name:    "unknown base still adds keys",
+			example: "PORT=3000\n# Neu\nNEW_KEY=1\n",
+			user:    "PORT=3000",
+			want:    "PORT=3000\n\n" + mergeHeader + "\n# Neu\nNEW_KEY=1\n",
+			added:   []string{"NEW_KEY"},
+		},
+		{
+			name:    "existing values kept",
+			base:    "PORT=3000\nNODE_ENV=development\n",
+			example: "PORT=3000\nNODE_ENV=production\n",
+			user:    "# eigener Kommentar\nNODE_ENV=test\nPORT=4000\n",
+			want:    "# eigener Kommentar\nNODE_ENV=test\nPORT=4000\n",
+		},
+		{
+			name:    "untouched default updated",
+			base:    "NODE_ENV=development\n",
+			example: "NODE_ENV=production\n",
+			user:    "NODE_ENV=development # Standard\n",
+			want:    "NODE_ENV=production\n",
+			updated: []string{"NODE_ENV"},
+		},
+		{
+			name:    "key deleted by the user stays deleted",
+			base:    "PORT=3000\nOPEN_BROWSER=true\n",
+			example: "PORT=3000\nOPEN_BROWSER=true\n",
+			user:    "PORT=3000\n",
+			want:    "PORT=3000\n",
+		},
+		{
+			name:    "CRLF line endings",
+			base:    "PORT=3000\r\n",
+			example: "PORT=3000\r\n# Log level\r\nLOG_LEVEL=info\r\n",
+			user:    "PORT=3000\r\nNODE_ENV=test\r\n",
+			want:    "PORT=3000\r\nNODE_ENV=test\r\n\r\n" + mergeHeader + "\r\n# Log level\r\nLOG_LEVEL=info\r\n",
+			added:   []string{"LOG_LEVEL"},
+		},
+		{
+			name:    "quoted values",
+			base:    "TITLE=\"Mein Stream\"\n",
+			example: "TITLE=\"Mein Stream\"\nGREETING='Hallo # Welt'\n",
+			user:    "TITLE='Mein Stream'\n",
+			want:    "TITLE='Mein Stream'\n\n" + mergeHeader + "\nGREETING='Hallo # Welt'\n",
+			added:   []string{"GREETING"},
+		},
+		{
+			name:    "duplicate keys: the first assignment counts",
+			base:    "PORT=3000\n",
+			example: "PORT=3005\n",
+			user:    "PORT=3000\nPORT=4000\n",
+			want:    "PORT=3005\nPORT=4000\n",
+			updated: []string{"PORT"},
+		},
+		{
+			name:    "duplicate keys in the example are added once",
+			example: "# A\nDEBUG=false\n# B\nDEBUG=true\n",
+			user:    "",
+			want:    mergeHeader + "\n# A\nDEBUG=false\n",
+			added:   []string{"DEBUG"},
+		},
+	}
+	for _, tt := range tests {
+		var base *File
+		if tt.base != "" {
+			base = Parse([]byte(tt.base))
+		}
+		user := Parse([]byte(tt.user))
+		res := Merge(base, Parse([]byte(tt.example)), user, mergeDate)
+		if got := string(user.Bytes()); got != tt.want {
+			t.Errorf("%s: merged file =\n%q\nwant\n%q", tt.name, got, tt.want)
+		}
+		if !reflect.DeepEqual(res.Added, tt.added) || !reflect.DeepEqual(res.Updated, tt.updated) {
+			t.Errorf("%s: Merge() = %+v, want added %v, updated %v", tt.name, res, tt.added, tt.updated)
+		}
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	f := Parse([]byte("A=plain # note\r\nB=\"two words\"\r\nC='a # b'\r\nD=\"x\\ny\"\r\nexport E=1\r\n# F=off\r\n# Note: G=prose\r\n"))
+	want := map[string]string{"A": "plain", "B": "two words", "C": "a # b", "D": "x\ny", "E": "1"}
+	for key, value := range want {
+		if got, ok := f.Get(key); !ok || got != value {
+			t.Errorf("Get(%s) = %q, %v, want %q", key, got, ok, value)
+		}
+	}
+	if _, ok := f.Get("F"); ok || !f.Has("F") {
+		t.Errorf("commented F: Get ok = %v, Has = %v", ok, f.Has("F"))
+	}
+	if f.Has("G") {
+		t.Errorf("prose comment parsed as assignment G")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want []string // keys with problems, sorted
+	}{
+		{"valid", "PORT=3000\nNODE_ENV=production\nOPEN_BROWSER=false\nOBS_WEBSOCKET_URL=ws://localhost:4455\n", nil},
+		{"empty values use defaults", "PORT=\nLOG_LEVEL=\n", nil},
+		{"commented out is ignored", "# PORT=abc\n", nil},
+		{"unknown keys are ignored", "MY_SETTING=whatever\n", nil},
+		{"port out of range", "PORT=70000\n", []string{"PORT"}},
+		{"quoted values", "PORT=\"3000\"\nOPEN_BROWSER='yes'\n", []string{"OPEN_BROWSER"}},
+		{"wrong url scheme", "OBS_WEBSOCKET_URL=http://localhost:4455\n", []string{"OBS_WEBSOCKET_URL"}},
+		{"several problems", "PORT=x\r\nLOG_LEVEL=loud\r\nNODE_ENV=test\r\n", []string{"LOG_LEVEL", "PORT"}},
+		{"duplicate keys: the first assignment counts", "PORT=abc\nPORT=3000\n", []string{"PORT"}},
+		{"duplicate keys: a later bad value is unused", "PORT=3000\nPORT=abc\n", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, p := range Validate(Parse([]byte(tt.env))) {
+			got = append(got, p.Key)
+			if p.Critical != (p.Key == "PORT") {
+				t.Errorf("%s: %s Critical = %v", tt.name, p.Key, p.Critical)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Validate() keys = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSetQuoting(t *testing.T) {
+	tests := []struct {
+		value string
+		raw   string // "" means refused
+	}{
+		{"plain", "K=plain"},
+		{"", "K="},
+		{`C:\Users\stream`, `K=C:\Users\stream`},
+		{"two words", "K='two words'"},
+		{`say "hi" # now`, `K='say "hi" # now'`},
+		{`a\nb c`, `K='a\nb c'`},
+		{"it's live", `K="it's live"`},
+		{"line1\nline2", `K="line1\nline2"`},
+		{`it's "live"`, "K=`it's \"live\"`"},
+		{`it's \ok`, "K=`it's \\ok`"},
+		{"it's \"live\" `now`", ""},
+		{"it's\n\"live\"", ""},
+	}
+	for _, tt := range tests {
+		f := Parse(nil)
+		err := f.Set("K", tt.value)
+		if tt.raw == "" {
+			if err == nil || len(f.Lines) != 0 {
+				t.Errorf("Set(%q) = %v with %d lines, want refused", tt.value, err, len(f.Lines))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Set(%q) = %v", tt.value, err)
+			continue
+		}
+		if got := f.Lines[0].Raw; got != tt.raw {
+			t.Errorf("Set(%q) wrote %s, want %s", tt.value, got, tt.raw)
+		}
+		if got, _ := Parse(f.Bytes()).Get("K"); got != tt.value {
+			t.Errorf("Set(%q) reads back as %q", tt.value, got)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package dotenv
+
+import (
+	"strings"
+)
+
+// Field describes one documented setting of .env.example for the settings page.
+type Field struct {
+	Key     string
+	Default string // value shown in the example
+	Help    string // comment block above the key
+	Section string // nearest section heading
+	Secret  bool
+}
+
+var secretMarkers = []string{"KEY", "PASSWORD", "SECRET", "TOKEN", "SESSION_ID"}
+
+// IsSecret reports whether a key holds a credential that must never be
+// echoed back in full.
+func IsSecret(key string) bool {
+	for _, m := range secretMarkers {
+		if strings.Contains(key, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fields lists every key documented in example, in file order.
+func Fields(example *File) []Field {
+	var fields []Field
+	seen := map[string]bool{}
+	section := ""
+	for i, l := range example.Lines {
+		if l.Key == "" {
+			// A comment right after a blank line opens a new section
+			if strings.HasPrefix(l.Raw, "#") && (i == 0 || strings.TrimSpace(example.Lines[i-1].Raw) == "") {
+				if title := commentText(l.Raw); title != "" && !strings.HasPrefix(title, "=") {
+					section = title
+				}
+			}
+			continue
+		}
+		if seen[l.Key] {
+			continue
+		}
+		seen[l.Key] = true
+
+		var help []string
+		for _, c := range commentBlock(example.Lines, i) {
+			text := commentText(c.Raw)
+			if text == "" || strings.Trim(text, "=-") == "" || text == section {
+				continue
+			}
+			help = append(help, text)
+		}
+		fields = append(fields, Field{
+			Key:     l.Key,
+			Default: l.Value,
+			Help:    strings.Join(help, "\n"),
+			Section: section,
+			Secret:  IsSecret(l.Key),
+		})
+	}
+	return fields
+}
+
+// Unset comments out the active assignment of key, keeping it documented.
+func (f *File) Unset(key string) {
+	if l := f.active(key); l != nil {
+		if raw, err := format(key, l.Value); err == nil {
+			l.Raw = "# " + raw
+		} else {
+			l.Raw = "# " + strings.TrimSpace(l.Raw)
+		}
+		l.Commented = true
+	}
+}
+
+func commentText(raw string) string {
+	return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(raw), "#"))
+}
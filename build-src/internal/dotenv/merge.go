@@ -0,0 +1,90 @@
+package dotenv
+
+import (
+	"fmt"
+	"time"
+)
+
+// MergeResult lists what Merge changed.
+type MergeResult struct {
+	Added   []string // keys introduced by the new .env.example
+	Updated []string // untouched defaults that changed upstream
+}
+
+// Changed reports whether the user's file was modified.
+func (r MergeResult) Changed() bool {
+	return len(r.Added) > 0 || len(r.Updated) > 0
+}
+
+// Merge brings newly introduced keys from example into user. base is the
+// .env.example the user's file was last merged with (nil if unknown); it
+// keeps keys the user deliberately deleted from coming back and tells
+// untouched defaults apart from values the user picked.
+//
+// New keys are appended together with the comment block that documents them
+// in the example, in the form they appear there (usually commented out).
+// The user's values, comments and ordering are never touched, except for
+// active values that still equal the old default and changed upstream.
+func Merge(base, example, user *File, now time.Time) MergeResult {
+	var res MergeResult
+
+	for _, key := range example.Keys() {
+		newValue, _ := example.Get(key)
+		if base == nil {
+			continue
+		}
+		oldValue, inBase := base.Get(key)
+		userValue, inUser := user.Get(key)
+		if inBase && inUser && userValue == oldValue && newValue != oldValue {
+			if user.Set(key, newValue) == nil {
+				res.Updated = append(res.Updated, key)
+			}
+		}
+	}
+
+	var block []*Line
+	for i, l := range example.Lines {
+		if l.Key == "" || user.Has(l.Key) || (base != nil && base.Has(l.Key)) || contains(res.Added, l.Key) {
+			continue
+		}
+		block = append(block, commentBlock(example.Lines, i)...)
+		block = append(block, &Line{Raw: l.Raw, Key: l.Key, Value: l.Value, Commented: l.Commented})
+		res.Added = append(res.Added, l.Key)
+	}
+
+	if len(block) > 0 {
+		if n := len(user.Lines); n > 0 && user.Lines[n-1].Raw != "" {
+			user.Lines = append(user.Lines, &Line{})
+		}
+		header := fmt.Sprintf("# --- Neu in dieser Version (vom Launcher ergänzt am %s) ---", now.Format("2006-01-02"))
+		user.Lines = append(user.Lines, &Line{Raw: header})
+		user.Lines = append(user.Lines, block...)
+	}
+	return res
+}
+
+// commentBlock returns the plain comment lines directly above lines[i].
+func commentBlock(lines []*Line, i int) []*Line {
+	start := i
+	for start > 0 {
+		prev := lines[start-1]
+		if prev.Key != "" || len(prev.Raw) == 0 || prev.Raw[0] != '#' {
+			break
+		}
+		start--
+	}
+	var out []*Line
+	for _, l := range lines[start:i] {
+		out = append(out, &Line{Raw: l.Raw})
+	}
+	return out
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
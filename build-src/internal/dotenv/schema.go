@@ -0,0 +1,121 @@
+package dotenv
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kind is the expected type of a variable.
+type Kind int
+
+const (
+	String Kind = iota
+	Int
+	Bool
+	URL
+	Enum
+)
+
+// Rule describes the valid values of one known variable.
+type Rule struct {
+	Kind     Kind
+	Min, Max int      // Int: inclusive range, ignored when both are 0
+	Values   []string // Enum: allowed values
+	Schemes  []string // URL: allowed schemes
+	Critical bool     // the server cannot start with an invalid value
+}
+
+// Schema lists the variables documented in .env.example that have a fixed
+// format. Unknown keys are not validated.
+var Schema = map[string]Rule{
+	"PORT":                      {Kind: Int, Min: 1, Max: 65535, Critical: true},
+	"NODE_ENV":                  {Kind: Enum, Values: []string{"development", "production", "test"}},
+	"OPEN_BROWSER":              {Kind: Bool},
+	"AUTO_START_ENABLED":        {Kind: Bool},
+	"AUTO_START_HIDDEN":         {Kind: Bool},
+	"OBS_WEBSOCKET_URL":         {Kind: URL, Schemes: []string{"ws", "wss"}},
+	"LOG_LEVEL":                 {Kind: Enum, Values: []string{"error", "warn", "info", "http", "verbose", "debug", "silly"}},
+	"LOG_TO_FILE":               {Kind: Bool},
+	"RATE_LIMIT_ENABLED":        {Kind: Bool},
+	"RATE_LIMIT_MAX_REQUESTS":   {Kind: Int, Min: 1, Max: 1000000},
+	"PLUGIN_AUTO_LOAD":          {Kind: Bool},
+	"SOUNDBOARD_PREVIEW_MODE":   {Kind: Enum, Values: []string{"client"}},
+	"AUTO_BACKUP_ENABLED":       {Kind: Bool},
+	"AUTO_BACKUP_INTERVAL":      {Kind: Int, Min: 60000, Max: 2147483647},
+	"ENABLE_TELEMETRY":          {Kind: Bool},
+	"ENABLE_AUTO_UPDATE":        {Kind: Bool},
+	"ENABLE_PLUGIN_MARKETPLACE": {Kind: Bool},
+}
+
+// Problem is a validation finding for one variable.
+type Problem struct {
+	Key      string
+	Value    string
+	Message  string
+	Critical bool
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s=%q: %s", p.Key, p.Value, p.Message)
+}
+
+// Validate checks every active assignment that has a Schema rule.
+func Validate(f *File) []Problem {
+	var problems []Problem
+	keys := f.Keys()
+	sort.Strings(keys)
+	for _, key := range keys {
+		rule, ok := Schema[key]
+		if !ok {
+			continue
+		}
+		value, _ := f.Get(key)
+		if msg := rule.check(value); msg != "" {
+			problems = append(problems, Problem{Key: key, Value: value, Message: msg, Critical: rule.Critical})
+		}
+	}
+	return problems
+}
+
+func (r Rule) check(value string) string {
+	if value == "" {
+		// Empty means "use the built-in default" for every documented key
+		return ""
+	}
+	switch r.Kind {
+	case Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return "muss eine ganze Zahl sein"
+		}
+		if (r.Min != 0 || r.Max != 0) && (n < r.Min || n > r.Max) {
+			return fmt.Sprintf("muss zwischen %d und %d liegen", r.Min, r.Max)
+		}
+	case Bool:
+		if value != "true" && value != "false" {
+			return "muss true oder false sein"
+		}
+	case Enum:
+		for _, v := range r.Values {
+			if value == v {
+				return ""
+			}
+		}
+		return "erlaubt: " + strings.Join(r.Values, ", ")
+	case URL:
+		u, err := url.Parse(value)
+		if err != nil || u.Host == "" {
+			return "ist keine gültige URL"
+		}
+		for _, s := range r.Schemes {
+			if u.Scheme == s {
+				return ""
+			}
+		}
+		return "Schema muss " + strings.Join(r.Schemes, " oder ") + " sein"
+	}
+	return ""
+}
@@ -0,0 +1,138 @@
+// Package instancelock makes sure only one launcher runs per installation.
+// The lock file lives in the app directory and holds the owner's PID and the
+// URL a second launch should open instead of starting over.
+package instancelock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileName is the name of the lock file inside the app directory.
+const FileName = "launcher.lock"
+
+// Info is the content of the lock file.
+type Info struct {
+	PID     int       `json:"pid"`
+	URL     string    `json:"url"`
+	Token   string    `json:"token,omitempty"`
+	Started time.Time `json:"started"`
+}
+
+// HeldError is returned by Acquire when another live launcher holds the lock.
+type HeldError struct {
+	Info Info
+}
+
+func (e *HeldError) Error() string {
+	return fmt.Sprintf("launcher already running (PID %d)", e.Info.PID)
+}
+
+// Lock is an acquired instance lock.
+type Lock struct {
+	file *os.File
+	info Info
+}
+
+// Acquire takes the advisory lock in dir. The lock file is never removed;
+// one left behind by a crashed launcher is not locked by anyone anymore
+// and is simply taken over.
+func Acquire(dir, url string) (*Lock, error) {
+	path := filepath.Join(dir, FileName)
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lockFile(f); err != nil {
+		info, readErr := readInfo(f)
+		f.Close()
+		if errors.Is(err, errLocked) {
+			if readErr != nil {
+				return nil, &HeldError{}
+			}
+			return nil, &HeldError{Info: info}
+		}
+		return nil, err
+	}
+
+	l := &Lock{
+		file: f,
+		info: Info{PID: os.Getpid(), URL: url, Started: time.Now()},
+	}
+	if err := l.write(); err != nil {
+		l.Release()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Read returns the lock file content without taking the lock.
+func Read(dir string) (Info, error) {
+	f, err := os.Open(filepath.Join(dir, FileName))
+	if err != nil {
+		return Info{}, err
+	}
+	defer f.Close()
+	return readInfo(f)
+}
+
+// SetURL updates the URL a second launch should open, e.g. once the
+// dashboard is reachable.
+func (l *Lock) SetURL(url string) error {
+	l.info.URL = url
+	return l.write()
+}
+
+// SetToken stores the splash server token so local scripts can call the
+// launcher's control endpoints. The file is only readable by its owner.
+func (l *Lock) SetToken(token string) error {
+	l.info.Token = token
+	return l.write()
+}
+
+// Release empties and unlocks the lock file. The file itself stays: a
+// launcher that opened it just before an unlink would lock the removed
+// file while the next one creates and locks a new one, and both would run.
+func (l *Lock) Release() {
+	if l == nil || l.file == nil {
+		return
+	}
+	// Empty before unlocking so a concurrent launcher never sees our stale content
+	l.file.Truncate(0)
+	unlockFile(l.file)
+	l.file.Close()
+	l.file = nil
+}
+
+func (l *Lock) write() error {
+	data, err := json.MarshalIndent(l.info, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := l.file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := l.file.WriteAt(data, 0); err != nil {
+		return err
+	}
+	return l.file.Sync()
+}
+
+func readInfo(f *os.File) (Info, error) {
+	var info Info
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return info, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return info, err
+	}
+	err = json.Unmarshal(data, &info)
+	return info, err
+}
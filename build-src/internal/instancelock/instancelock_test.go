@@ -0,0 +1,40 @@
+package instancelock
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAcquireRelease(t *testing.T) {
+	dir := t.TempDir()
+	first, err := Acquire(dir, "http://localhost:58734")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Acquire(dir, "")
+	var held *HeldError
+	if !errors.As(err, &held) {
+		t.Fatalf("second Acquire: got %v, want HeldError", err)
+	}
+	if held.Info.PID != os.Getpid() || held.Info.URL != "http://localhost:58734" {
+		t.Errorf("HeldError info = %+v", held.Info)
+	}
+
+	first.Release()
+	// The file stays, so every launcher locks the same inode
+	if _, err := os.Stat(filepath.Join(dir, FileName)); err != nil {
+		t.Fatalf("lock file removed: %v", err)
+	}
+	if info, err := Read(dir); err == nil {
+		t.Errorf("released lock still readable: %+v", info)
+	}
+
+	second, err := Acquire(dir, "")
+	if err != nil {
+		t.Fatalf("Acquire after Release: %v", err)
+	}
+	second.Release()
+}
@@ -0,0 +1,88 @@
+// Package logsink copies the launcher log to a console. When the launcher
+// runs as a systemd service, lines are written in the journal's stream
+// format instead: a "<N>" syslog priority per line and no timestamp, since
+// journald adds its own.
+package logsink
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"regexp"
+	"sync"
+)
+
+// Syslog priorities used in journal stream lines.
+const (
+	PriorityError   = 3
+	PriorityWarning = 4
+	PriorityInfo    = 6
+)
+
+// timestampRe matches the prefix written by log.LstdFlags.
+var timestampRe = regexp.MustCompile(`^\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2} `)
+
+// UnderJournald reports whether stdout is connected to the systemd journal.
+func UnderJournald() bool {
+	return os.Getenv("JOURNAL_STREAM") != ""
+}
+
+// Writer forwards complete lines to the underlying writer. It is safe for
+// concurrent use, so the launcher log and the server output can share it.
+type Writer struct {
+	mu       sync.Mutex
+	w        io.Writer
+	journald bool
+	priority int // for lines without an [ERROR]/[WARNING] marker
+	buf      []byte
+}
+
+// New returns a Writer for w. Lines without a level marker are logged with
+// the given default priority in journald mode.
+func New(w io.Writer, journald bool, priority int) *Writer {
+	return &Writer{w: w, journald: journald, priority: priority}
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.writeLine(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes a pending incomplete line.
+func (w *Writer) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		w.writeLine(w.buf)
+		w.buf = nil
+	}
+}
+
+func (w *Writer) writeLine(line []byte) {
+	line = bytes.TrimRight(line, "\r")
+	if !w.journald {
+		w.w.Write(append(line, '\n'))
+		return
+	}
+	line = timestampRe.ReplaceAll(line, nil)
+	prio := w.priority
+	switch {
+	case bytes.Contains(line, []byte("[ERROR]")):
+		prio = PriorityError
+	case bytes.Contains(line, []byte("[WARNING]")):
+		prio = PriorityWarning
+	}
+	out := append([]byte{'<', byte('0' + prio), '>'}, line...)
+	w.w.Write(append(out, '\n'))
+}
@@ -0,0 +1,111 @@
+// Package nodeabi detects native Node.js modules (better-sqlite3) that were
+// compiled for a different Node.js ABI than the runtime the launcher is about
+// to start the server with.
+package nodeabi
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// StampFile is written into node_modules after every successful install or
+// rebuild and holds the process.versions.modules value it was built for.
+const StampFile = ".ltth-node-abi"
+
+// NativeModules lists the packages that ship ABI-specific binaries and have
+// to be rebuilt when the Node.js runtime changes.
+var NativeModules = []string{"better-sqlite3"}
+
+// probeScript loads the native binding. better-sqlite3 only loads its addon
+// when the first database is opened, so a plain require() is not enough.
+const probeScript = `const Database = require('better-sqlite3'); new Database(':memory:').close();`
+
+var moduleVersionRe = regexp.MustCompile(`NODE_MODULE_VERSION (\d+)`)
+
+// Result describes the outcome of Check.
+type Result struct {
+	RuntimeABI  string // process.versions.modules of the current node binary
+	RecordedABI string // value from StampFile, empty if never recorded
+	CompiledABI string // ABI reported by a failed probe, if any
+	Mismatch    bool
+	Reason      string
+}
+
+// RuntimeABI returns process.versions.modules for the given node binary.
+func RuntimeABI(nodePath string) (string, error) {
+	out, err := exec.Command(nodePath, "-p", "process.versions.modules").Output()
+	if err != nil {
+		return "", fmt.Errorf("cannot read Node.js ABI: %v", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// RecordedABI returns the ABI stored in node_modules, or "" if none exists.
+func RecordedABI(appDir string) string {
+	data, err := os.ReadFile(stampPath(appDir))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// Record stores abi as the ABI the current node_modules were built for.
+func Record(appDir, abi string) error {
+	return os.WriteFile(stampPath(appDir), []byte(abi+"\n"), 0644)
+}
+
+// Probe loads better-sqlite3 with the given node binary. If loading fails
+// because of an ABI mismatch, the compiled ABI is returned together with the
+// combined output; any other failure is reported as a plain error.
+func Probe(nodePath, appDir string) (compiledABI string, output string, err error) {
+	cmd := exec.Command(nodePath, "-e", probeScript)
+	cmd.Dir = appDir
+	var buf bytes.Buffer
+	cmd.Stdout = &buf
+	cmd.Stderr = &buf
+	runErr := cmd.Run()
+	output = buf.String()
+	if runErr == nil {
+		return "", output, nil
+	}
+	if m := moduleVersionRe.FindStringSubmatch(output); m != nil {
+		return m[1], output, nil
+	}
+	return "", output, fmt.Errorf("probe failed: %v", runErr)
+}
+
+// Check compares the recorded ABI with the runtime and probes the native
+// module. A clean probe always wins over a stale stamp, and a missing stamp
+// alone is not treated as a mismatch.
+func Check(nodePath, appDir string) (Result, error) {
+	res := Result{RecordedABI: RecordedABI(appDir)}
+
+	abi, err := RuntimeABI(nodePath)
+	if err != nil {
+		return res, err
+	}
+	res.RuntimeABI = abi
+
+	compiled, _, probeErr := Probe(nodePath, appDir)
+	switch {
+	case compiled != "":
+		res.CompiledABI = compiled
+		res.Mismatch = true
+		res.Reason = fmt.Sprintf("better-sqlite3 wurde für NODE_MODULE_VERSION %s gebaut, Node.js benötigt %s", compiled, res.RuntimeABI)
+	case probeErr == nil:
+		// Loads fine - the stamp (if any) is merely outdated
+	case res.RecordedABI != "" && res.RecordedABI != res.RuntimeABI:
+		res.Mismatch = true
+		res.Reason = fmt.Sprintf("node_modules wurden für ABI %s gebaut, Node.js nutzt ABI %s", res.RecordedABI, res.RuntimeABI)
+	}
+	return res, nil
+}
+
+func stampPath(appDir string) string {
+	return filepath.Join(appDir, "node_modules", StampFile)
+}
@@ -0,0 +1,115 @@
+package nodeabi
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// stubNode stands in for node: "-p" prints ABI 115 and "-e" (the probe)
+// fails like Node.js does when the fixture addon holds another ABI.
+const stubNode = `#!/bin/sh
+case "$1" in
+-p) echo 115 ;;
+-e)
+	addon=node_modules/better-sqlite3/build/Release/better_sqlite3.node
+	[ -f node_modules/better-sqlite3/package.json ] || { echo "Error: Cannot find module 'better-sqlite3'" >&2; exit 1; }
+	abi=$(cat "$addon")
+	if [ "$abi" != 115 ]; then
+		echo "Error: The module '$PWD/$addon'" >&2
+		echo "was compiled against a different Node.js version using" >&2
+		echo "NODE_MODULE_VERSION $abi. This version of Node.js requires" >&2
+		echo "NODE_MODULE_VERSION 115. Please try re-compiling or re-installing" >&2
+		exit 1
+	fi ;;
+esac
+`
+
+// fixture lays out app/node_modules with better-sqlite3 built for addonABI
+// ("" for not installed) and the stamp of the last install.
+func fixture(t *testing.T, addonABI, stamp string) (nodePath, appDir string) {
+	t.Helper()
+	dir := t.TempDir()
+	nodePath = filepath.Join(dir, "node")
+	if err := os.WriteFile(nodePath, []byte(stubNode), 0755); err != nil {
+		t.Fatal(err)
+	}
+	appDir = filepath.Join(dir, "app")
+	pkg := filepath.Join(appDir, "node_modules", "better-sqlite3")
+	if err := os.MkdirAll(filepath.Join(pkg, "build", "Release"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if addonABI != "" {
+		os.WriteFile(filepath.Join(pkg, "package.json"), []byte(`{"name": "better-sqlite3", "version": "11.9.0"}`), 0644)
+		os.WriteFile(filepath.Join(pkg, "build", "Release", "better_sqlite3.node"), []byte(addonABI), 0644)
+	}
+	if stamp != "" {
+		if err := Record(appDir, stamp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return nodePath, appDir
+}
+
+func TestCheck(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the node stub is a shell script")
+	}
+	tests := []struct {
+		name            string
+		addonABI, stamp string
+		mismatch        bool
+		compiled        string
+	}{
+		{"matching build", "115", "115", false, ""},
+		{"first start without stamp", "115", "", false, ""},
+		{"built for an older Node.js", "108", "108", true, "108"},
+		{"stale stamp, addon loads", "115", "108", false, ""},
+		{"probe fails otherwise, stamp differs", "", "108", true, ""},
+		{"probe fails otherwise, stamp matches", "", "115", false, ""},
+		{"probe fails otherwise, no stamp", "", "", false, ""},
+	}
+	for _, tt := range tests {
+		nodePath, appDir := fixture(t, tt.addonABI, tt.stamp)
+		res, err := Check(nodePath, appDir)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if res.RuntimeABI != "115" || res.RecordedABI != tt.stamp {
+			t.Errorf("%s: runtime %q, recorded %q", tt.name, res.RuntimeABI, res.RecordedABI)
+		}
+		if res.Mismatch != tt.mismatch || res.CompiledABI != tt.compiled {
+			t.Errorf("%s: Check() = %+v, want mismatch %v, compiled %q", tt.name, res, tt.mismatch, tt.compiled)
+		}
+		if tt.mismatch && !strings.Contains(res.Reason, "115") {
+			t.Errorf("%s: reason %q does not name the runtime ABI", tt.name, res.Reason)
+		}
+	}
+}
+
+func TestRecord(t *testing.T) {
+	appDir := t.TempDir()
+	if got := RecordedABI(appDir); got != "" {
+		t.Errorf("RecordedABI without stamp = %q", got)
+	}
+	os.MkdirAll(filepath.Join(appDir, "node_modules"), 0755)
+	if err := Record(appDir, "127"); err != nil {
+		t.Fatal(err)
+	}
+	if got := RecordedABI(appDir); got != "127" {
+		t.Errorf("RecordedABI = %q, want 127", got)
+	}
+}
+
+func TestModuleVersionPattern(t *testing.T) {
+	// Message of Node.js 20 loading an addon built with Node.js 18
+	out := `Error: The module '\\?\C:\LTTH\app\node_modules\better-sqlite3\build\Release\better_sqlite3.node'
+was compiled against a different Node.js version using
+NODE_MODULE_VERSION 108. This version of Node.js requires
+NODE_MODULE_VERSION 115. Please try re-compiling or re-installing`
+	if m := moduleVersionRe.FindStringSubmatch(out); m == nil || m[1] != "108" {
+		t.Errorf("compiled ABI = %v, want 108", m)
+	}
+}
@@ -0,0 +1,220 @@
+// Package npmcache manages the npm cache the launchers keep inside the
+// installation, so a reinstall of node_modules reuses downloaded packages
+// and can run without network once the cache is filled.
+package npmcache
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/unzip"
+)
+
+// DirName is the default cache directory next to the launcher.
+const DirName = "npm-cache"
+
+// Usage is the size of a cache directory.
+type Usage struct {
+	Files int
+	Bytes int64
+}
+
+// Args returns the npm arguments that make npm use dir as its cache and,
+// once it holds packages, prefer it over the registry. An empty dir
+// leaves npm's own cache setting alone.
+func Args(dir string) []string {
+	if dir == "" {
+		return nil
+	}
+	args := []string{"--cache", dir}
+	if Warm(dir) {
+		args = append(args, "--prefer-offline")
+	}
+	return args
+}
+
+// Warm reports whether dir holds at least one cached package index entry.
+func Warm(dir string) bool {
+	warm := false
+	filepath.WalkDir(filepath.Join(dir, "_cacache", "index-v5"), func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			warm = true
+			return fs.SkipAll
+		}
+		return nil
+	})
+	return warm
+}
+
+// Size adds up the regular files below dir. A missing dir is empty.
+func Size(dir string) (Usage, error) {
+	var u Usage
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		u.Files++
+		u.Bytes += info.Size()
+		return nil
+	})
+	return u, err
+}
+
+// LockedPackages returns "name@version" for every registry package in
+// appDir/package-lock.json (lockfile v1 to v3), sorted and without
+// duplicates. Linked, bundled, git and file: dependencies are left out.
+func LockedPackages(appDir string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(appDir, "package-lock.json"))
+	if err != nil {
+		return nil, err
+	}
+	var lock struct {
+		Packages     map[string]lockedPackage `json:"packages"`
+		Dependencies map[string]lockedPackage `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("package-lock.json: %v", err)
+	}
+
+	seen := map[string]bool{}
+	if len(lock.Packages) > 0 {
+		for key, p := range lock.Packages {
+			i := strings.LastIndex(key, "node_modules/")
+			if i < 0 {
+				continue // "" is the app itself, other keys are workspaces
+			}
+			name := p.Name
+			if name == "" {
+				name = key[i+len("node_modules/"):]
+			}
+			p.add(name, seen)
+		}
+	} else {
+		var walk func(map[string]lockedPackage)
+		walk = func(deps map[string]lockedPackage) {
+			for name, p := range deps {
+				p.add(name, seen)
+				walk(p.Dependencies)
+			}
+		}
+		walk(lock.Dependencies)
+	}
+
+	specs := make([]string, 0, len(seen))
+	for spec := range seen {
+		specs = append(specs, spec)
+	}
+	sort.Strings(specs)
+	return specs, nil
+}
+
+type lockedPackage struct {
+	Name         string                   `json:"name"` // set for aliases
+	Version      string                   `json:"version"`
+	Resolved     string                   `json:"resolved"`
+	Link         bool                     `json:"link"`
+	Bundled      bool                     `json:"bundled"`
+	Dependencies map[string]lockedPackage `json:"dependencies"` // lockfile v1 only
+}
+
+func (p lockedPackage) add(name string, seen map[string]bool) {
+	if p.Link || p.Bundled || p.Version == "" {
+		return
+	}
+	if p.Resolved != "" && !strings.HasPrefix(p.Resolved, "http://") && !strings.HasPrefix(p.Resolved, "https://") {
+		return
+	}
+	// Lockfile v1 writes aliases as "npm:name@version"
+	if alias, ok := strings.CutPrefix(p.Version, "npm:"); ok {
+		seen[alias] = true
+		return
+	}
+	if strings.Contains(p.Version, ":") {
+		return
+	}
+	seen[name+"@"+p.Version] = true
+}
+
+// Export writes the cache directory into a zip file that Import can read
+// on another installation. Cached packages are compressed tarballs, so
+// the entries are stored, not deflated.
+func Export(dir, file string) (Usage, error) {
+	var u Usage
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".npm-cache-export-*.tmp")
+	if err != nil {
+		return u, err
+	}
+	defer os.Remove(tmp.Name())
+
+	zw := zip.NewWriter(tmp)
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() || p == tmp.Name() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		hdr.Method = zip.Store
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if _, err := io.Copy(w, f); err != nil {
+			return err
+		}
+		u.Files++
+		u.Bytes += info.Size()
+		return nil
+	})
+	if err == nil {
+		err = zw.Close()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return u, err
+	}
+	return u, os.Rename(tmp.Name(), file)
+}
+
+// Import adds the packages of an exported cache to dir. The cache is
+// content-addressed, so existing entries are kept.
+func Import(file, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return unzip.Extract(file, dir, false)
+}
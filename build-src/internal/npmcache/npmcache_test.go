@@ -0,0 +1,201 @@
+package npmcache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFiles creates the files below dir, keyed by slash-separated path.
+// Names ending in a slash are empty directories.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if strings.HasSuffix(name, "/") {
+			if err := os.MkdirAll(path, 0755); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestArgs(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string // inside the cache dir, nil: no cache dir
+		want  string
+	}{
+		{"missing cache", nil, "--cache CACHE"},
+		{"empty cache", map[string]string{}, "--cache CACHE"},
+		{"only temporary files", map[string]string{"_cacache/tmp/abc": "x", "_logs/debug.log": "x"}, "--cache CACHE"},
+		{"empty index", map[string]string{"_cacache/index-v5/1a/": ""}, "--cache CACHE"},
+		{"filled cache", map[string]string{"_cacache/index-v5/1a/2b/3c4d": "entry"}, "--cache CACHE --prefer-offline"},
+	}
+	for _, tt := range tests {
+		dir := filepath.Join(t.TempDir(), DirName)
+		if tt.files != nil {
+			writeFiles(t, dir, tt.files)
+		}
+		got := strings.ReplaceAll(strings.Join(Args(dir), " "), dir, "CACHE")
+		if got != tt.want {
+			t.Errorf("%s: Args() = %s, want %s", tt.name, got, tt.want)
+		}
+		if warm := strings.HasSuffix(tt.want, "--prefer-offline"); Warm(dir) != warm {
+			t.Errorf("%s: Warm() = %v, want %v", tt.name, !warm, warm)
+		}
+	}
+	if args := Args(""); args != nil {
+		t.Errorf(`Args("") = %v, want npm's own cache`, args)
+	}
+}
+
+func TestLockedPackages(t *testing.T) {
+	tests := []struct {
+		name string
+		lock string
+		want string
+	}{
+		{
+			name: "lockfile v3",
+			lock: `{
+				"lockfileVersion": 3,
+				"packages": {
+					"": {"name": "ltth", "version": "1.2.1"},
+					"node_modules/express": {"version": "4.21.2", "resolved": "https://registry.npmjs.org/express/-/express-4.21.2.tgz"},
+					"node_modules/express/node_modules/debug": {"version": "2.6.9", "resolved": "https://registry.npmjs.org/debug/-/debug-2.6.9.tgz"},
+					"node_modules/debug": {"version": "4.3.7", "resolved": "https://registry.npmjs.org/debug/-/debug-4.3.7.tgz"},
+					"node_modules/@scope/pkg": {"version": "1.0.0", "resolved": "https://registry.npmjs.org/@scope/pkg/-/pkg-1.0.0.tgz"},
+					"node_modules/other-express": {"name": "express", "version": "4.21.2", "resolved": "https://registry.npmjs.org/express/-/express-4.21.2.tgz"},
+					"node_modules/old-ws": {"name": "ws", "version": "7.5.10", "resolved": "https://registry.npmjs.org/ws/-/ws-7.5.10.tgz"},
+					"node_modules/local": {"resolved": "plugins/local", "link": true},
+					"plugins/local": {"version": "0.1.0"},
+					"node_modules/from-file": {"version": "1.0.0", "resolved": "file:../vendor/from-file-1.0.0.tgz"},
+					"node_modules/from-git": {"version": "2.0.0", "resolved": "git+ssh://git@github.com/user/from-git.git#0123abc"},
+					"node_modules/npm/node_modules/abbrev": {"version": "2.0.0", "bundled": true}
+				}
+			}`,
+			want: "@scope/pkg@1.0.0 debug@2.6.9 debug@4.3.7 express@4.21.2 ws@7.5.10",
+		},
+		{
+			name: "lockfile v1",
+			lock: `{
+				"lockfileVersion": 1,
+				"dependencies": {
+					"express": {
+						"version": "4.21.2",
+						"resolved": "https://registry.npmjs.org/express/-/express-4.21.2.tgz",
+						"dependencies": {
+							"debug": {"version": "2.6.9", "resolved": "https://registry.npmjs.org/debug/-/debug-2.6.9.tgz"}
+						}
+					},
+					"debug": {"version": "4.3.7"},
+					"old-ws": {"version": "npm:ws@7.5.10", "resolved": "https://registry.npmjs.org/ws/-/ws-7.5.10.tgz"},
+					"from-file": {"version": "file:../vendor/from-file"},
+					"from-git": {"version": "github:user/from-git#0123abc", "from": "github:user/from-git"},
+					"abbrev": {"version": "2.0.0", "bundled": true}
+				}
+			}`,
+			want: "debug@2.6.9 debug@4.3.7 express@4.21.2 ws@7.5.10",
+		},
+		{
+			name: "lockfile v2 prefers packages",
+			lock: `{
+				"lockfileVersion": 2,
+				"packages": {"node_modules/ws": {"version": "8.18.0", "resolved": "https://registry.npmjs.org/ws/-/ws-8.18.0.tgz"}},
+				"dependencies": {"ws": {"version": "8.18.0"}, "stale": {"version": "1.0.0"}}
+			}`,
+			want: "ws@8.18.0",
+		},
+		{
+			name: "no dependencies",
+			lock: `{"lockfileVersion": 3, "packages": {"": {"name": "ltth"}}}`,
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		appDir := t.TempDir()
+		writeFiles(t, appDir, map[string]string{"package-lock.json": tt.lock})
+		got, err := LockedPackages(appDir)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("%s: LockedPackages() = %v, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLockedPackagesErrors(t *testing.T) {
+	appDir := t.TempDir()
+	if _, err := LockedPackages(appDir); !os.IsNotExist(err) {
+		t.Errorf("missing package-lock.json: %v", err)
+	}
+	writeFiles(t, appDir, map[string]string{"package-lock.json": "{"})
+	if _, err := LockedPackages(appDir); err == nil {
+		t.Error("broken package-lock.json accepted")
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	files := map[string]string{
+		"_cacache/index-v5/1a/2b/3c4d":               `{"key":"make-fetch-happen:request-cache:https://registry.npmjs.org/ws/-/ws-8.18.0.tgz"}`,
+		"_cacache/content-v2/sha512/ab/cd/ef":        strings.Repeat("tarball", 1000),
+		"_logs/2025-01-02T20_00_00_000Z-debug-0.log": "0 verbose cli",
+	}
+	src := filepath.Join(t.TempDir(), DirName)
+	writeFiles(t, src, files)
+	want, err := Size(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Exporting into the cache itself must not pick up the archive
+	archive := filepath.Join(src, "export.zip")
+	got, err := Export(src, archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("Export() = %+v, want %+v", got, want)
+	}
+
+	// The target already holds another package, which stays
+	dst := filepath.Join(t.TempDir(), DirName)
+	writeFiles(t, dst, map[string]string{"_cacache/index-v5/99/88/7766": "other"})
+	if err := Import(archive, dst); err != nil {
+		t.Fatal(err)
+	}
+	files["_cacache/index-v5/99/88/7766"] = "other"
+	for name, content := range files {
+		data, err := os.ReadFile(filepath.Join(dst, filepath.FromSlash(name)))
+		if err != nil || string(data) != content {
+			t.Errorf("%s after import: %q, %v", name, data, err)
+		}
+	}
+	if u, _ := Size(dst); u.Files != len(files) {
+		t.Errorf("imported cache has %d files, want %d", u.Files, len(files))
+	}
+	if !Warm(dst) {
+		t.Error("imported cache is not warm")
+	}
+}
+
+func TestSizeMissingDir(t *testing.T) {
+	u, err := Size(filepath.Join(t.TempDir(), "missing"))
+	if err != nil || u != (Usage{}) {
+		t.Errorf("Size() = %+v, %v", u, err)
+	}
+}
@@ -0,0 +1,97 @@
+// Package npmdeps compares the dependencies declared in app/package.json
+// with the packages installed in app/node_modules, so entries added by
+// plugins or app updates are installed before the server fails with
+// "Cannot find module".
+package npmdeps
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/semver"
+)
+
+// Stale is a declared dependency that is missing or out of range.
+type Stale struct {
+	Name      string
+	Spec      string // as declared in package.json, e.g. "^4.18.0"
+	Installed string // version in node_modules, "" if missing
+}
+
+func (s Stale) String() string {
+	if s.Installed == "" {
+		return fmt.Sprintf("%s fehlt (%s)", s.Name, s.Spec)
+	}
+	return fmt.Sprintf("%s %s passt nicht zu %s", s.Name, s.Installed, s.Spec)
+}
+
+// Arg is the package argument for a targeted npm install.
+func (s Stale) Arg() string {
+	return s.Name + "@" + s.Spec
+}
+
+// Check returns the "dependencies" of appDir/package.json that are not
+// installed or whose installed version does not satisfy the declared
+// range, sorted by name. Specs that are not version ranges (git URLs,
+// file: paths, tags) are only checked for presence.
+func Check(appDir string) ([]Stale, error) {
+	data, err := os.ReadFile(filepath.Join(appDir, "package.json"))
+	if err != nil {
+		return nil, err
+	}
+	var pkg struct {
+		Dependencies map[string]string `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, fmt.Errorf("package.json: %v", err)
+	}
+
+	var stale []Stale
+	for name, spec := range pkg.Dependencies {
+		installed, ok := installedVersion(appDir, name)
+		if !ok {
+			stale = append(stale, Stale{Name: name, Spec: spec})
+			continue
+		}
+		r, err := semver.ParseRange(rangeOf(spec))
+		if err != nil {
+			continue
+		}
+		if v, err := semver.Parse(installed); err != nil || !r.Contains(v) {
+			stale = append(stale, Stale{Name: name, Spec: spec, Installed: installed})
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool { return stale[i].Name < stale[j].Name })
+	return stale, nil
+}
+
+// installedVersion reads node_modules/<name>/package.json; ok is false if
+// the package is not installed.
+func installedVersion(appDir, name string) (version string, ok bool) {
+	data, err := os.ReadFile(filepath.Join(appDir, "node_modules", filepath.FromSlash(name), "package.json"))
+	if err != nil {
+		return "", false
+	}
+	var pkg struct {
+		Version string `json:"version"`
+	}
+	json.Unmarshal(data, &pkg)
+	return pkg.Version, true
+}
+
+// rangeOf returns the version range of an alias: "npm:@scope/pkg@^1.0.0"
+// -> "^1.0.0". Other specs are returned unchanged.
+func rangeOf(spec string) string {
+	alias, ok := strings.CutPrefix(spec, "npm:")
+	if !ok {
+		return spec
+	}
+	if i := strings.LastIndexByte(alias, '@'); i > 0 {
+		return alias[i+1:]
+	}
+	return ""
+}
@@ -0,0 +1,267 @@
+package plugins
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/unzip"
+)
+
+// InstallFile records where a plugin installed by the launcher came from.
+// Plugins without it are bundled with LTTH and are left to the app update.
+const InstallFile = ".ltth-install.json"
+
+// Source is the install record of a third-party plugin.
+type Source struct {
+	Source      string    `json:"source"` // URL or absolute path of the zip
+	Version     string    `json:"version"`
+	SHA256      string    `json:"sha256"` // of the zip
+	InstalledAt time.Time `json:"installedAt"`
+}
+
+// NPMFunc installs packages into the node_modules of pluginDir.
+type NPMFunc func(pluginDir string, packages []string) error
+
+var downloadClient = &http.Client{Timeout: 5 * time.Minute}
+
+// IsURL reports whether source is downloaded rather than read from disk.
+func IsURL(source string) bool {
+	return strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://")
+}
+
+// Download stores the zip at url in a temporary file. The caller removes it.
+func Download(url string) (string, error) {
+	resp, err := downloadClient.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Download fehlgeschlagen: HTTP %d", resp.StatusCode)
+	}
+
+	tmp, err := os.CreateTemp("", "ltth-plugin-*.zip")
+	if err != nil {
+		return "", err
+	}
+	n, err := io.Copy(tmp, io.LimitReader(resp.Body, unzip.MaxSize+1))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && n > unzip.MaxSize {
+		err = unzip.ErrTooLarge
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+// ReadSource returns the install record of the plugin in pluginDir, or nil
+// for a bundled plugin.
+func ReadSource(pluginDir string) (*Source, error) {
+	data, err := os.ReadFile(filepath.Join(pluginDir, InstallFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var src Source
+	if err := json.Unmarshal(data, &src); err != nil {
+		return nil, fmt.Errorf("%s: %v", InstallFile, err)
+	}
+	return &src, nil
+}
+
+// Install unpacks the plugin in zipPath into dir/<id>. The manifest must
+// pass the schema check and its id must be new; with replace set to an id
+// installed by the launcher, that plugin is replaced instead. Declared npm
+// packages the app does not provide are installed into the plugin's own
+// node_modules before anything in dir changes.
+func Install(dir, zipPath, source, replace string, npm NPMFunc) (*Plugin, error) {
+	sum, err := fileSHA256(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	stage, err := os.MkdirTemp(dir, "_install-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(stage)
+
+	if err := unzip.Extract(zipPath, stage, false); err != nil {
+		return nil, err
+	}
+	root, err := findManifest(stage)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(root, ManifestName))
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]json.RawMessage
+	var m Manifest
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s nicht lesbar: %v", ManifestName, err)
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%s ungültig: %v", ManifestName, err)
+	}
+	// The id becomes the directory name
+	if !idPattern.MatchString(m.ID) {
+		return nil, fmt.Errorf("ungültige Plugin-id %q (erlaubt: a-z, A-Z, 0-9, _ und -)", m.ID)
+	}
+
+	list, err := List(dir)
+	if err != nil {
+		return nil, err
+	}
+	ids := map[string]int{m.ID: 1}
+	var existing *Plugin
+	for i := range list {
+		ids[list[i].ID]++
+		if list[i].ID == m.ID {
+			existing = &list[i]
+		}
+	}
+	target := filepath.Join(dir, m.ID)
+	switch {
+	case replace == "" && existing != nil:
+		return nil, fmt.Errorf("Plugin %s ist bereits installiert (%s)", m.ID, existing.Dir)
+	case replace == "":
+		if _, err := os.Stat(target); err == nil {
+			return nil, fmt.Errorf("Verzeichnis %s existiert bereits", target)
+		}
+	case replace != m.ID:
+		return nil, fmt.Errorf("das Archiv enthält Plugin %s statt %s", m.ID, replace)
+	case existing == nil:
+		return nil, fmt.Errorf("Plugin %q nicht gefunden", replace)
+	case existing.Dir != target:
+		return nil, fmt.Errorf("Plugin %s liegt in %s statt %s", m.ID, existing.Dir, target)
+	default:
+		if src, err := ReadSource(target); err != nil || src == nil {
+			return nil, fmt.Errorf("Plugin %s wurde nicht mit dem Launcher installiert", m.ID)
+		}
+		ids[m.ID]--
+	}
+
+	appDir := filepath.Dir(dir)
+	if missing := missingPackages(root, appDir, m.Dependencies); len(missing) > 0 {
+		if err := npm(root, missing); err != nil {
+			return nil, fmt.Errorf("npm install %s fehlgeschlagen: %v", strings.Join(missing, " "), err)
+		}
+	}
+	var errs []string
+	for _, prob := range validate(root, appDir, raw, ids) {
+		if prob.Critical {
+			errs = append(errs, prob.Message)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("Plugin %s ist fehlerhaft: %s", m.ID, strings.Join(errs, "; "))
+	}
+
+	// MkdirTemp creates the stage private, the server must be able to read it
+	if err := os.Chmod(root, 0755); err != nil {
+		return nil, err
+	}
+	record, err := json.MarshalIndent(Source{Source: source, Version: m.Version, SHA256: sum, InstalledAt: time.Now()}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(root, InstallFile), record, 0644); err != nil {
+		return nil, err
+	}
+
+	// Swap directories last, the old version comes back if the move fails
+	if existing != nil {
+		old := filepath.Join(dir, "_previous-"+m.ID)
+		os.RemoveAll(old)
+		if err := os.Rename(target, old); err != nil {
+			return nil, err
+		}
+		if err := os.Rename(root, target); err != nil {
+			os.Rename(old, target)
+			return nil, err
+		}
+		os.RemoveAll(old)
+	} else if err := os.Rename(root, target); err != nil {
+		return nil, err
+	}
+	return Find(dir, m.ID)
+}
+
+// Remove deletes a plugin installed by the launcher together with its
+// entry in plugins_state.json. Bundled plugins are refused, the next app
+// update would bring them back.
+func Remove(dir, id string) error {
+	p, err := Find(dir, id)
+	if err != nil {
+		return err
+	}
+	src, err := ReadSource(p.Dir)
+	if err != nil {
+		return err
+	}
+	if src == nil {
+		return fmt.Errorf("Plugin %s gehört zu LTTH und kann nur deaktiviert werden", id)
+	}
+	if err := os.RemoveAll(p.Dir); err != nil {
+		return err
+	}
+
+	state, err := readState(dir)
+	if err != nil {
+		return err
+	}
+	if _, ok := state[id]; !ok {
+		return nil
+	}
+	delete(state, id)
+	return writeState(dir, state)
+}
+
+// findManifest accepts plugin.json at the top of the archive or inside a
+// single folder, like the dashboard upload does.
+func findManifest(stage string) (string, error) {
+	if _, err := os.Stat(filepath.Join(stage, ManifestName)); err == nil {
+		return stage, nil
+	}
+	entries, err := os.ReadDir(stage)
+	if err != nil {
+		return "", err
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(stage, e.Name(), ManifestName)); err == nil {
+			return filepath.Join(stage, e.Name()), nil
+		}
+	}
+	return "", fmt.Errorf("keine %s im Archiv gefunden", ManifestName)
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
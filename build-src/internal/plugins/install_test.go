@@ -0,0 +1,155 @@
+package plugins
+
+import (
+	"archive/zip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// pluginZip writes a zip with the given files below a top-level folder, the
+// way plugins are usually packed.
+func pluginZip(t *testing.T, files map[string]string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "plugin.zip")
+	f, err := os.Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, body := range files {
+		w, err := zw.Create("my-plugin/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(body))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	return p
+}
+
+func manifest(id, version string) string {
+	return `{"id": "` + id + `", "name": "Test", "version": "` + version + `", "entry": "main.js"}`
+}
+
+func pluginsDir(t *testing.T) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "app", "plugins")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func noNPM(pluginDir string, packages []string) error {
+	return errors.New("npm must not run")
+}
+
+// leftovers returns the stage and backup directories Install left behind.
+func leftovers(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), "_") {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
+
+func TestInstallAndReplace(t *testing.T) {
+	dir := pluginsDir(t)
+	v1 := pluginZip(t, map[string]string{"plugin.json": manifest("demo", "1.0.0"), "main.js": "// v1", "old.js": ""})
+
+	p, err := Install(dir, v1, "https://example.com/demo.zip", "", noNPM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.ID != "demo" || p.Version != "1.0.0" || p.Dir != filepath.Join(dir, "demo") {
+		t.Errorf("Install() = %+v", p)
+	}
+	src, err := ReadSource(p.Dir)
+	if err != nil || src == nil || src.Source != "https://example.com/demo.zip" || src.Version != "1.0.0" || len(src.SHA256) != 64 {
+		t.Errorf("install record %+v, %v", src, err)
+	}
+
+	if _, err := Install(dir, v1, "https://example.com/demo.zip", "", noNPM); err == nil {
+		t.Errorf("second Install without replace succeeded")
+	}
+
+	v2 := pluginZip(t, map[string]string{"plugin.json": manifest("demo", "2.0.0"), "main.js": "// v2"})
+	p, err = Install(dir, v2, "https://example.com/demo.zip", "demo", noNPM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Version != "2.0.0" {
+		t.Errorf("replaced version = %s", p.Version)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "demo", "main.js")); string(data) != "// v2" {
+		t.Errorf("main.js = %q after replace", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "demo", "old.js")); !os.IsNotExist(err) {
+		t.Errorf("file of the old version survived the replace")
+	}
+	if names := leftovers(t, dir); len(names) != 0 {
+		t.Errorf("left behind %v", names)
+	}
+}
+
+func TestInstallReplaceRollback(t *testing.T) {
+	dir := pluginsDir(t)
+	v1 := pluginZip(t, map[string]string{"plugin.json": manifest("demo", "1.0.0"), "main.js": "// v1"})
+	if _, err := Install(dir, v1, "/plugins/demo.zip", "", noNPM); err != nil {
+		t.Fatal(err)
+	}
+	bundled := filepath.Join(dir, "bundled")
+	os.MkdirAll(bundled, 0755)
+	os.WriteFile(filepath.Join(bundled, ManifestName), []byte(manifest("bundled", "1.0.0")), 0644)
+	os.WriteFile(filepath.Join(bundled, "main.js"), nil, 0644)
+
+	npmFails := func(pluginDir string, packages []string) error {
+		return errors.New("network down")
+	}
+	tests := []struct {
+		name    string
+		files   map[string]string
+		replace string
+		npm     NPMFunc
+		want    string // part of the error
+	}{
+		{"entry missing", map[string]string{"plugin.json": manifest("demo", "2.0.0")}, "demo", noNPM, "Einstiegsdatei"},
+		{"npm fails", map[string]string{"plugin.json": `{"id": "demo", "name": "Test", "entry": "main.js", "dependencies": ["left-pad"]}`, "main.js": ""}, "demo", npmFails, "network down"},
+		{"other id", map[string]string{"plugin.json": manifest("other", "2.0.0"), "main.js": ""}, "demo", noNPM, "statt demo"},
+		{"bundled plugin", map[string]string{"plugin.json": manifest("bundled", "2.0.0"), "main.js": ""}, "bundled", noNPM, "nicht mit dem Launcher installiert"},
+		{"not installed", map[string]string{"plugin.json": manifest("missing", "2.0.0"), "main.js": ""}, "missing", noNPM, "nicht gefunden"},
+		{"bad id", map[string]string{"plugin.json": manifest("../demo", "2.0.0"), "main.js": ""}, "demo", noNPM, "ungültige Plugin-id"},
+		{"wrong field type", map[string]string{"plugin.json": `{"id": "demo", "name": "Test", "entry": "main.js", "enabled": "yes"}`, "main.js": ""}, "demo", noNPM, "plugin.json ungültig"},
+		{"broken json", map[string]string{"plugin.json": `{"id": "demo",`, "main.js": ""}, "demo", noNPM, "nicht lesbar"},
+		{"no manifest", map[string]string{"main.js": ""}, "demo", noNPM, "keine plugin.json"},
+	}
+	for _, tt := range tests {
+		_, err := Install(dir, pluginZip(t, tt.files), "/plugins/demo.zip", tt.replace, tt.npm)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: Install() = %v, want error containing %q", tt.name, err, tt.want)
+		}
+		p, err := Find(dir, "demo")
+		if err != nil || p.Version != "1.0.0" {
+			t.Errorf("%s: installed plugin after failure = %+v, %v", tt.name, p, err)
+		}
+		if data, _ := os.ReadFile(filepath.Join(dir, "demo", "main.js")); string(data) != "// v1" {
+			t.Errorf("%s: main.js = %q, want the old version", tt.name, data)
+		}
+		if names := leftovers(t, dir); len(names) != 0 {
+			t.Errorf("%s: left behind %v", tt.name, names)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+// Package plugins lists the plugins in app/plugins and switches them on or
+// off the way modules/plugin-loader.js expects: the manifest's "enabled" is
+// only the default, a per-plugin "enabled" in plugins_state.json wins and
+// "disabled": true in the manifest turns a plugin off for good.
+package plugins
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// ManifestName is the manifest file inside every plugin directory.
+	ManifestName = "plugin.json"
+	// StateFile is the plugin loader's state file inside the plugins directory.
+	StateFile = "plugins_state.json"
+)
+
+// Manifest is the part of plugin.json the launcher shows.
+type Manifest struct {
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	Version      string            `json:"version"`
+	Description  string            `json:"description"`
+	Descriptions map[string]string `json:"descriptions"`
+	Author       string            `json:"author"`
+	Entry        string            `json:"entry"`
+	Type         string            `json:"type"`
+	DevStatus    string            `json:"devStatus"`
+	Enabled      *bool             `json:"enabled"`
+	Disabled     bool              `json:"disabled"` // permanently disabled, the loader never loads it
+	Permissions  []string          `json:"permissions"`
+	Dependencies json.RawMessage   `json:"dependencies"` // npm packages, or plugin ids by kind
+}
+
+// Plugin is one directory below app/plugins.
+type Plugin struct {
+	Manifest
+	Dir      string
+	Enabled  bool      // the loader tries to load it on the next start
+	Problems []Problem // findings of the manifest and dependency check
+	Error    string    // the critical problems, the plugin would fail to load
+}
+
+// Description returns the German description if there is one.
+func (p *Plugin) Description() string {
+	if d := p.Descriptions["de"]; d != "" {
+		return d
+	}
+	return p.Manifest.Description
+}
+
+// CanEnable reports why the loader would refuse to load the plugin.
+func (p *Plugin) CanEnable() error {
+	if p.Error != "" {
+		return fmt.Errorf("Plugin %s kann nicht aktiviert werden: %s", p.ID, p.Error)
+	}
+	if p.Disabled {
+		return fmt.Errorf("Plugin %s ist dauerhaft deaktiviert (\"disabled\" in %s)", p.ID, ManifestName)
+	}
+	return nil
+}
+
+// List returns all plugins in dir sorted by id, each checked with the
+// manifest schema. Directories without a plugin.json or starting with "_"
+// (the upload directory) are skipped like the loader does.
+func List(dir string) ([]Plugin, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	state, err := readState(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Plugin
+	var raws []map[string]json.RawMessage
+	ids := map[string]int{}
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), "_") {
+			continue
+		}
+		pluginDir := filepath.Join(dir, e.Name())
+		data, err := os.ReadFile(filepath.Join(pluginDir, ManifestName))
+		if os.IsNotExist(err) {
+			continue
+		}
+		p := Plugin{Dir: pluginDir}
+		var raw map[string]json.RawMessage
+		if err == nil {
+			err = json.Unmarshal(data, &raw)
+		}
+		if err != nil {
+			p.Problems = []Problem{{Message: ManifestName + " nicht lesbar: " + err.Error(), Critical: true}}
+		} else {
+			// Type errors are reported by validate, the rest is still usable
+			json.Unmarshal(data, &p.Manifest)
+		}
+		if p.ID == "" {
+			p.ID = e.Name()
+		}
+		ids[p.ID]++
+		list = append(list, p)
+		raws = append(raws, raw)
+	}
+
+	for i := range list {
+		p := &list[i]
+		if raws[i] != nil {
+			p.Problems = validate(p.Dir, filepath.Dir(dir), raws[i], ids)
+		}
+		if ids[p.ID] > 1 {
+			p.Problems = append(p.Problems, Problem{Message: fmt.Sprintf("id %q wird von mehreren Plugins verwendet", p.ID), Critical: true})
+		}
+		var errs []string
+		for _, prob := range p.Problems {
+			if prob.Critical {
+				errs = append(errs, prob.Message)
+			}
+		}
+		p.Error = strings.Join(errs, "; ")
+		p.Enabled = !p.Disabled && enabled(p.Manifest, state[p.ID])
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list, nil
+}
+
+// Find returns the plugin with the given id.
+func Find(dir, id string) (*Plugin, error) {
+	list, err := List(dir)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		if list[i].ID == id {
+			return &list[i], nil
+		}
+	}
+	return nil, fmt.Errorf("Plugin %q nicht gefunden", id)
+}
+
+// SetEnabled stores the plugin's new state in plugins_state.json. Other
+// fields the loader keeps there (loadedAt, reloadCount, ...) are preserved.
+// It must not be used while the server runs: the loader keeps the state in
+// memory and overwrites the file on its next change.
+func SetEnabled(dir, id string, on bool) error {
+	p, err := Find(dir, id)
+	if err != nil {
+		return err
+	}
+	if on {
+		if err := p.CanEnable(); err != nil {
+			return err
+		}
+	}
+
+	state, err := readState(dir)
+	if err != nil {
+		return err
+	}
+	entry := state[id]
+	if entry == nil {
+		entry = map[string]json.RawMessage{}
+		state[id] = entry
+	}
+	entry["enabled"] = json.RawMessage(fmt.Sprint(on))
+	return writeState(dir, state)
+}
+
+// enabled mirrors the loader: state wins, then the manifest, default on.
+func enabled(m Manifest, state map[string]json.RawMessage) bool {
+	var on bool
+	if raw, ok := state["enabled"]; ok && json.Unmarshal(raw, &on) == nil {
+		return on
+	}
+	return m.Enabled == nil || *m.Enabled
+}
+
+func readState(dir string) (map[string]map[string]json.RawMessage, error) {
+	state := map[string]map[string]json.RawMessage{}
+	data, err := os.ReadFile(filepath.Join(dir, StateFile))
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return state, nil
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("%s: %v", StateFile, err)
+	}
+	return state, nil
+}
+
+// writeState replaces the state file atomically, formatted like the
+// loader's JSON.stringify(state, null, 2).
+func writeState(dir string, state map[string]map[string]json.RawMessage) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, StateFile+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, StateFile))
+}
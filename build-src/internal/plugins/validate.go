@@ -0,0 +1,244 @@
+package plugins
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Problem is one finding of the manifest check. Critical problems make the
+// plugin fail to load.
+type Problem struct {
+	Message  string
+	Critical bool
+}
+
+func (p Problem) String() string { return p.Message }
+
+var (
+	idPattern      = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	versionPattern = regexp.MustCompile(`^\d+\.\d+\.\d+([-+][0-9A-Za-z.-]+)?$`)
+)
+
+// fieldTypes is the manifest schema: required fields first, everything
+// else is optional. Unknown fields are allowed, plugins use them for their
+// own settings.
+var fieldTypes = []struct {
+	name     string
+	kind     string
+	required bool
+}{
+	{"id", "string", true},
+	{"name", "string", true},
+	{"entry", "string", true},
+	{"version", "string", false},
+	{"description", "string", false},
+	{"author", "string", false},
+	{"type", "string", false},
+	{"devStatus", "string", false},
+	{"uiPath", "string", false},
+	{"enabled", "bool", false},
+	{"disabled", "bool", false},
+	{"permissions", "strings", false},
+	{"tags", "strings", false},
+	{"features", "array", false},
+	{"descriptions", "object", false},
+	{"config", "object", false},
+	{"settings", "object", false},
+}
+
+// validate checks a plugin.json against the schema, the entry file and the
+// declared dependencies. installed lists the plugin ids present, for
+// dependencies on other plugins.
+func validate(pluginDir, appDir string, raw map[string]json.RawMessage, installed map[string]int) []Problem {
+	var problems []Problem
+	add := func(critical bool, format string, args ...interface{}) {
+		problems = append(problems, Problem{Message: fmt.Sprintf(format, args...), Critical: critical})
+	}
+
+	for _, f := range fieldTypes {
+		value, ok := raw[f.name]
+		if !ok || string(value) == "null" {
+			if f.required {
+				add(true, "Pflichtfeld %q fehlt", f.name)
+			}
+			continue
+		}
+		if !hasKind(value, f.kind) {
+			add(f.required, "%q muss %s sein", f.name, kindNames[f.kind])
+		}
+	}
+
+	var id, entry, version string
+	json.Unmarshal(raw["id"], &id)
+	json.Unmarshal(raw["entry"], &entry)
+	json.Unmarshal(raw["version"], &version)
+
+	if id != "" && !idPattern.MatchString(id) {
+		add(false, "id %q enthält Zeichen außer a-z, 0-9, _ und - (Dashboard-API kann es nicht ansprechen)", id)
+	}
+	if base := filepath.Base(pluginDir); id != "" && id != base {
+		add(false, "id %q passt nicht zum Verzeichnis %q (Neuladen und Löschen im Dashboard schlagen fehl)", id, base)
+	}
+	if version != "" && !versionPattern.MatchString(version) {
+		add(false, "version %q ist keine SemVer-Version (z.B. 1.2.0)", version)
+	}
+
+	if entry != "" {
+		target := filepath.Join(pluginDir, filepath.FromSlash(entry))
+		if filepath.IsAbs(entry) || !strings.HasPrefix(target, filepath.Clean(pluginDir)+string(filepath.Separator)) {
+			add(true, "entry %q zeigt aus dem Plugin-Verzeichnis heraus", entry)
+		} else if info, err := os.Stat(target); err != nil || info.IsDir() {
+			add(true, "Einstiegsdatei %s fehlt", entry)
+		}
+	}
+
+	if deps, ok := raw["dependencies"]; ok {
+		problems = append(problems, checkDependencies(pluginDir, appDir, deps, installed)...)
+	}
+	return problems
+}
+
+// checkDependencies accepts the two forms used by the bundled plugins: a
+// list of npm packages, or an object of plugin id lists ("optional" ones
+// only produce a warning when missing).
+func checkDependencies(pluginDir, appDir string, raw json.RawMessage, installed map[string]int) []Problem {
+	var problems []Problem
+	add := func(critical bool, format string, args ...interface{}) {
+		problems = append(problems, Problem{Message: fmt.Sprintf(format, args...), Critical: critical})
+	}
+
+	var packages []string
+	if json.Unmarshal(raw, &packages) == nil {
+		declared := packageJSONDeps(appDir)
+		for _, pkg := range packages {
+			if resolvable(pluginDir, appDir, pkg) {
+				continue
+			}
+			if declared[packageName(pkg)] {
+				add(true, "npm-Paket %s fehlt in node_modules (npm install ausführen)", pkg)
+			} else {
+				add(true, "npm-Paket %s fehlt und steht nicht in package.json (npm install %s)", pkg, packageName(pkg))
+			}
+		}
+		return problems
+	}
+
+	var pluginDeps map[string][]string
+	if json.Unmarshal(raw, &pluginDeps) != nil {
+		add(false, "%q muss eine Liste von npm-Paketen oder ein Objekt mit Plugin-Listen sein", "dependencies")
+		return problems
+	}
+	for kind, ids := range pluginDeps {
+		for _, dep := range ids {
+			switch {
+			case installed[dep] > 0:
+			case kind == "optional":
+				add(false, "optionales Plugin %s ist nicht installiert", dep)
+			default:
+				add(true, "benötigtes Plugin %s ist nicht installiert", dep)
+			}
+		}
+	}
+	return problems
+}
+
+// resolvable looks for the package the way require() does from the
+// plugin: its own node_modules, then every parent up to the app directory.
+func resolvable(pluginDir, appDir, pkg string) bool {
+	name := packageName(pkg)
+	if nodeBuiltins[strings.TrimPrefix(name, "node:")] {
+		return true
+	}
+	appDir = filepath.Clean(appDir)
+	for dir := filepath.Clean(pluginDir); ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, "node_modules", filepath.FromSlash(name), "package.json")); err == nil {
+			return true
+		}
+		if dir == appDir || dir == filepath.Dir(dir) {
+			return false
+		}
+	}
+}
+
+// missingPackages returns the declared npm dependencies require() would
+// not find from pluginDir. Dependencies on other plugins are ignored.
+func missingPackages(pluginDir, appDir string, raw json.RawMessage) []string {
+	var packages, missing []string
+	if json.Unmarshal(raw, &packages) != nil {
+		return nil
+	}
+	for _, pkg := range packages {
+		if !resolvable(pluginDir, appDir, pkg) {
+			missing = append(missing, packageName(pkg))
+		}
+	}
+	return missing
+}
+
+// packageName strips a subpath: "lodash/merge" -> "lodash", "@scope/pkg/x" -> "@scope/pkg".
+func packageName(spec string) string {
+	parts := strings.Split(spec, "/")
+	if strings.HasPrefix(spec, "@") && len(parts) > 1 {
+		return parts[0] + "/" + parts[1]
+	}
+	return parts[0]
+}
+
+// packageJSONDeps returns every package appDir/package.json installs.
+func packageJSONDeps(appDir string) map[string]bool {
+	deps := map[string]bool{}
+	data, err := os.ReadFile(filepath.Join(appDir, "package.json"))
+	if err != nil {
+		return deps
+	}
+	var pkg map[string]map[string]string
+	json.Unmarshal(data, &pkg)
+	for _, field := range []string{"dependencies", "optionalDependencies", "devDependencies"} {
+		for name := range pkg[field] {
+			deps[name] = true
+		}
+	}
+	return deps
+}
+
+var kindNames = map[string]string{
+	"string":  "ein Text",
+	"bool":    "true oder false",
+	"strings": "eine Liste von Texten",
+	"array":   "eine Liste",
+	"object":  "ein Objekt",
+}
+
+func hasKind(raw json.RawMessage, kind string) bool {
+	switch kind {
+	case "string":
+		var s string
+		return json.Unmarshal(raw, &s) == nil
+	case "bool":
+		var b bool
+		return json.Unmarshal(raw, &b) == nil
+	case "strings":
+		var list []string
+		return json.Unmarshal(raw, &list) == nil
+	case "array":
+		var list []json.RawMessage
+		return json.Unmarshal(raw, &list) == nil
+	case "object":
+		var obj map[string]json.RawMessage
+		return json.Unmarshal(raw, &obj) == nil
+	}
+	return false
+}
+
+// nodeBuiltins are modules require() resolves without node_modules.
+var nodeBuiltins = map[string]bool{
+	"assert": true, "buffer": true, "child_process": true, "crypto": true, "dgram": true,
+	"dns": true, "events": true, "fs": true, "http": true, "https": true, "net": true,
+	"os": true, "path": true, "querystring": true, "readline": true, "stream": true,
+	"string_decoder": true, "timers": true, "tls": true, "url": true, "util": true,
+	"worker_threads": true, "zlib": true,
+}
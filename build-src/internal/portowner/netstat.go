@@ -0,0 +1,33 @@
+package portowner
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// listeningPID returns the PID of the socket listening on port in the
+// output of Windows' netstat -ano. The state column is translated
+// ("LISTENING", "ABHÖREN"), so a listening socket is recognized by its
+// foreign address with port 0 ("0.0.0.0:0", "[::]:0").
+func listeningPID(netstat []byte, port int) int {
+	suffix := ":" + strconv.Itoa(port)
+	scanner := bufio.NewScanner(bytes.NewReader(netstat))
+	for scanner.Scan() {
+		// Proto  Local Address  Foreign Address  State  PID
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 || !strings.EqualFold(fields[0], "TCP") {
+			continue
+		}
+		if !strings.HasSuffix(fields[1], suffix) || !strings.HasSuffix(fields[2], ":0") {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[len(fields)-1])
+		if err != nil || pid == 0 {
+			continue
+		}
+		return pid
+	}
+	return 0
+}
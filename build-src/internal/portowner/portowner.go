@@ -0,0 +1,96 @@
+// Package portowner resolves which process is listening on a local TCP port
+// so the launcher can tell a stale LTTH server apart from an unrelated program.
+package portowner
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotFound is returned when no listening process could be resolved,
+// e.g. because the socket belongs to another user.
+var ErrNotFound = errors.New("no owning process found")
+
+// Owner describes the process holding a port.
+type Owner struct {
+	PID     int
+	Exe     string // full path of the executable, if known
+	Cmdline string // full command line, if known
+	Cwd     string // working directory, if known
+}
+
+func (o *Owner) String() string {
+	name := o.Exe
+	if name == "" {
+		name = "unbekannt"
+	}
+	return fmt.Sprintf("PID %d (%s)", o.PID, name)
+}
+
+// Find returns the process listening on the given TCP port.
+func Find(port int) (*Owner, error) {
+	return find(port)
+}
+
+// IsLTTHServer reports whether the owner is a Node.js process running this
+// installation's launch.js or server.js: the script is given as a path
+// inside appDir, or relative with appDir as working directory. Paths are
+// compared as whole components, so "app-old" is not "app".
+func (o *Owner) IsLTTHServer(appDir string) bool {
+	exe := path.Base(normalize(o.Exe))
+	if o.Exe != "" && !strings.HasPrefix(exe, "node") {
+		return false
+	}
+
+	cmdline := normalize(o.Cmdline)
+	app := normalize(filepath.Clean(appDir))
+	for _, script := range []string{"launch.js", "server.js"} {
+		if containsPath(cmdline, app+"/"+script) {
+			return true
+		}
+	}
+	if o.Cwd == "" || normalize(filepath.Clean(o.Cwd)) != app {
+		return false
+	}
+	return strings.Contains(cmdline, "launch.js") || strings.Contains(cmdline, "server.js")
+}
+
+// normalize makes paths of either platform comparable as strings.
+func normalize(p string) string {
+	return strings.ToLower(strings.ReplaceAll(p, `\`, "/"))
+}
+
+// containsPath reports whether path appears in cmdline as a whole argument
+// or quoted argument, not as the tail of a longer path.
+func containsPath(cmdline, p string) bool {
+	for i := 0; ; {
+		j := strings.Index(cmdline[i:], p)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(p)
+		before := start == 0 || strings.ContainsRune(" \t\"'", rune(cmdline[start-1]))
+		after := end == len(cmdline) || strings.ContainsRune(" \t\"'", rune(cmdline[end]))
+		if before && after {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+// FreePort returns the first port in [from, to] that can be bound on localhost.
+func FreePort(from, to int) (int, error) {
+	for port := from; port <= to; port++ {
+		l, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", port))
+		if err != nil {
+			continue
+		}
+		l.Close()
+		return port, nil
+	}
+	return 0, fmt.Errorf("kein freier Port zwischen %d und %d", from, to)
+}
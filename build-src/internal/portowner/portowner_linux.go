@@ -0,0 +1,96 @@
+package portowner
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// tcpListen is the st column value for sockets in LISTEN state.
+const tcpListen = "0A"
+
+func find(port int) (*Owner, error) {
+	inodes := map[string]bool{}
+	for _, table := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		if err := listeningInodes(table, port, inodes); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	if len(inodes) == 0 {
+		return nil, ErrNotFound
+	}
+
+	procs, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range procs {
+		pid, err := strconv.Atoi(p.Name())
+		if err != nil {
+			continue
+		}
+		if ownsInode(pid, inodes) {
+			return describe(pid), nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// listeningInodes collects the socket inodes listening on port from a
+// /proc/net/tcp style table.
+func listeningInodes(table string, port int, inodes map[string]bool) error {
+	f, err := os.Open(table)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	wantPort := fmt.Sprintf("%04X", port)
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != tcpListen {
+			continue
+		}
+		local := fields[1]
+		idx := strings.LastIndex(local, ":")
+		if idx < 0 || local[idx+1:] != wantPort {
+			continue
+		}
+		inodes[fields[9]] = true
+	}
+	return scanner.Err()
+}
+
+func ownsInode(pid int, inodes map[string]bool) bool {
+	fdDir := filepath.Join("/proc", strconv.Itoa(pid), "fd")
+	fds, err := os.ReadDir(fdDir)
+	if err != nil {
+		return false
+	}
+	for _, fd := range fds {
+		target, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+		if err != nil || !strings.HasPrefix(target, "socket:[") {
+			continue
+		}
+		if inodes[strings.TrimSuffix(strings.TrimPrefix(target, "socket:["), "]")] {
+			return true
+		}
+	}
+	return false
+}
+
+func describe(pid int) *Owner {
+	dir := filepath.Join("/proc", strconv.Itoa(pid))
+	o := &Owner{PID: pid}
+	o.Exe, _ = os.Readlink(filepath.Join(dir, "exe"))
+	o.Cwd, _ = os.Readlink(filepath.Join(dir, "cwd"))
+	if data, err := os.ReadFile(filepath.Join(dir, "cmdline")); err == nil {
+		o.Cmdline = strings.TrimSpace(strings.ReplaceAll(string(data), "\x00", " "))
+	}
+	return o
+}
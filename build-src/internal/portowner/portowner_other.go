@@ -0,0 +1,52 @@
+//go:build !linux && !windows
+
+package portowner
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// find uses lsof, which ships with macOS and the BSDs.
+func find(port int) (*Owner, error) {
+	out, err := exec.Command("lsof", "-nP", fmt.Sprintf("-iTCP:%d", port), "-sTCP:LISTEN", "-Fp").Output()
+	if err != nil || len(out) == 0 {
+		return nil, ErrNotFound
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "p") {
+			continue
+		}
+		pid, err := strconv.Atoi(line[1:])
+		if err != nil {
+			continue
+		}
+		return describe(pid), nil
+	}
+	return nil, ErrNotFound
+}
+
+func describe(pid int) *Owner {
+	o := &Owner{PID: pid}
+	if out, err := exec.Command("ps", "-o", "comm=", "-p", strconv.Itoa(pid)).Output(); err == nil {
+		o.Exe = strings.TrimSpace(string(out))
+	}
+	if out, err := exec.Command("ps", "-o", "command=", "-p", strconv.Itoa(pid)).Output(); err == nil {
+		o.Cmdline = strings.TrimSpace(string(out))
+	}
+	if out, err := exec.Command("lsof", "-a", "-p", strconv.Itoa(pid), "-d", "cwd", "-Fn").Output(); err == nil {
+		for _, line := range strings.Split(string(out), "\n") {
+			if strings.HasPrefix(line, "n") {
+				o.Cwd = line[1:]
+			}
+		}
+	}
+	return o
+}
@@ -0,0 +1,55 @@
+package portowner
+
+import "testing"
+
+func TestListeningPID(t *testing.T) {
+	netstat := []byte("\r\n" +
+		"Aktive Verbindungen\r\n\r\n" +
+		"  Proto  Lokale Adresse         Remoteadresse          Status           PID\r\n" +
+		"  TCP    0.0.0.0:135            0.0.0.0:0              ABHÖREN          1044\r\n" +
+		"  TCP    127.0.0.1:3000         127.0.0.1:52311        HERGESTELLT      7712\r\n" +
+		"  TCP    127.0.0.1:13000        0.0.0.0:0              ABHÖREN          5555\r\n" +
+		"  TCP    0.0.0.0:3000           0.0.0.0:0              ABHÖREN          7712\r\n" +
+		"  TCP    [::]:3001              [::]:0                 LISTENING        8080\r\n" +
+		"  TCP    127.0.0.1:52311        127.0.0.1:3000         TIME_WAIT        0\r\n")
+
+	tests := []struct {
+		port int
+		want int
+	}{
+		{3000, 7712},
+		{3001, 8080},
+		{135, 1044},
+		{52311, 0}, // only a client connection
+		{4000, 0},
+	}
+	for _, tt := range tests {
+		if got := listeningPID(netstat, tt.port); got != tt.want {
+			t.Errorf("listeningPID(%d) = %d, want %d", tt.port, got, tt.want)
+		}
+	}
+}
+
+func TestIsLTTHServer(t *testing.T) {
+	tests := []struct {
+		name   string
+		owner  Owner
+		appDir string
+		want   bool
+	}{
+		{"absolute launch.js", Owner{Exe: `C:\Program Files\nodejs\node.exe`, Cmdline: `"C:\Program Files\nodejs\node.exe" "C:\LTTH\app\launch.js"`}, `C:\LTTH\app`, true},
+		{"case and separators", Owner{Exe: "node.exe", Cmdline: `node c:/ltth/APP/server.js`}, `C:\LTTH\app`, true},
+		{"unix", Owner{Exe: "/usr/bin/node", Cmdline: "/usr/bin/node /opt/ltth/app/launch.js"}, "/opt/ltth/app", true},
+		{"sibling directory", Owner{Exe: "node.exe", Cmdline: `node C:\LTTH\app-old\launch.js`}, `C:\LTTH\app`, false},
+		{"nested path", Owner{Exe: "/usr/bin/node", Cmdline: "node /x/opt/ltth/app/launch.js"}, "/opt/ltth/app", false},
+		{"relative with cwd", Owner{Exe: "node", Cmdline: "node launch.js", Cwd: "/opt/ltth/app/"}, "/opt/ltth/app", true},
+		{"relative in sibling cwd", Owner{Exe: "node", Cmdline: "node launch.js", Cwd: "/opt/ltth/app-old"}, "/opt/ltth/app", false},
+		{"other script", Owner{Exe: "node", Cmdline: "node /opt/ltth/app/tools/other.js"}, "/opt/ltth/app", false},
+		{"not node", Owner{Exe: "/usr/bin/python3", Cmdline: "python3 /opt/ltth/app/launch.js"}, "/opt/ltth/app", false},
+	}
+	for _, tt := range tests {
+		if got := tt.owner.IsLTTHServer(tt.appDir); got != tt.want {
+			t.Errorf("%s: IsLTTHServer = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package portowner
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"unsafe"
+
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/procutil"
+	"golang.org/x/sys/windows"
+)
+
+func find(port int) (*Owner, error) {
+	cmd := exec.Command("netstat", "-ano", "-p", "TCP")
+	procutil.HideWindow(cmd)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("netstat: %v", err)
+	}
+
+	if pid := listeningPID(out, port); pid != 0 {
+		return describe(pid), nil
+	}
+	return nil, ErrNotFound
+}
+
+func describe(pid int) *Owner {
+	o := &Owner{PID: pid}
+	script := fmt.Sprintf(`$p = Get-CimInstance Win32_Process -Filter "ProcessId=%d"; $p.ExecutablePath; $p.CommandLine`, pid)
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	procutil.HideWindow(cmd)
+	out, err := cmd.Output()
+	if err != nil {
+		return o
+	}
+	lines := strings.SplitN(strings.ReplaceAll(string(out), "\r\n", "\n"), "\n", 2)
+	o.Exe = strings.TrimSpace(lines[0])
+	if len(lines) > 1 {
+		o.Cmdline = strings.TrimSpace(lines[1])
+	}
+	o.Cwd = processCwd(pid)
+	return o
+}
+
+// processCwd reads the current directory from the process parameters in
+// the PEB of another process. It needs a process of the same user and
+// bitness; otherwise the directory stays unknown.
+func processCwd(pid int) string {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION|windows.PROCESS_VM_READ, false, uint32(pid))
+	if err != nil {
+		return ""
+	}
+	defer windows.CloseHandle(h)
+
+	// Addresses of the other process are kept as uintptr, never as Go pointers
+	var info struct {
+		exitStatus uintptr
+		peb        uintptr
+		_          [4]uintptr // affinity mask, base priority, PID, parent PID
+	}
+	if unsafe.Sizeof(info) != unsafe.Sizeof(windows.PROCESS_BASIC_INFORMATION{}) {
+		return ""
+	}
+	if err := windows.NtQueryInformationProcess(h, windows.ProcessBasicInformation, unsafe.Pointer(&info), uint32(unsafe.Sizeof(info)), nil); err != nil {
+		return ""
+	}
+
+	var params uintptr
+	if !readMemory(h, info.peb+unsafe.Offsetof(windows.PEB{}.ProcessParameters), unsafe.Pointer(&params), unsafe.Sizeof(params)) {
+		return ""
+	}
+	var dir struct {
+		length, maxLength uint16
+		buffer            uintptr
+	}
+	offset := unsafe.Offsetof(windows.RTL_USER_PROCESS_PARAMETERS{}.CurrentDirectory) + unsafe.Offsetof(windows.CURDIR{}.DosPath)
+	if !readMemory(h, params+offset, unsafe.Pointer(&dir), unsafe.Sizeof(dir)) || dir.length == 0 {
+		return ""
+	}
+	buf := make([]uint16, dir.length/2)
+	if !readMemory(h, dir.buffer, unsafe.Pointer(&buf[0]), uintptr(dir.length)) {
+		return ""
+	}
+	return filepath.Clean(windows.UTF16ToString(buf))
+}
+
+func readMemory(h windows.Handle, addr uintptr, dst unsafe.Pointer, size uintptr) bool {
+	var n uintptr
+	err := windows.ReadProcessMemory(h, addr, (*byte)(dst), size, &n)
+	return err == nil && n == size
+}
@@ -0,0 +1,177 @@
+// Package preflight checks free disk space and write access before the
+// launchers run npm install, extract archives or create log files, so a
+// full disk or a read-only installation (Program Files without admin
+// rights) is reported with a fix instead of an npm stack trace.
+package preflight
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Estimated space needed on the app volume.
+const (
+	MinFree     uint64 = 200 << 20 // logs, database growth and backups of a normal start
+	InstallFree uint64 = 1 << 30   // additionally for a full npm install including the npm cache
+)
+
+// Problem is a finding of Check. Critical problems make the start fail.
+type Problem struct {
+	Message  string // what is wrong and how to fix it
+	Critical bool
+	Dir      string // directory without write access, "" for missing space
+}
+
+func (p Problem) String() string { return p.Message }
+
+// Check verifies that the volume of appDir has room for a start, plus a
+// full npm install if install is set, and that app/, app/logs,
+// app/node_modules and configDir are writable. Only existing logs and
+// node_modules directories are checked; missing ones are created in app/.
+func Check(appDir, configDir string, install bool) []Problem {
+	var problems []Problem
+	need := MinFree
+	if install {
+		need += InstallFree
+	}
+	if free, err := FreeSpace(appDir); err == nil && free < need {
+		problems = append(problems, Problem{
+			Message:  fmt.Sprintf("Nur %s frei auf dem Laufwerk von %s, benötigt werden etwa %s. Speicher freigeben (Papierkorb, alte Backups, \"launcher cache prune\") oder LTTH auf ein anderes Laufwerk verschieben", megabytes(free), appDir, megabytes(need)),
+			Critical: true,
+		})
+	}
+
+	dirs := []string{appDir}
+	for _, sub := range []string{"logs", "node_modules"} {
+		if info, err := os.Stat(filepath.Join(appDir, sub)); err == nil && info.IsDir() {
+			dirs = append(dirs, filepath.Join(appDir, sub))
+		}
+	}
+	for _, dir := range dirs {
+		if err := Writable(dir); err == nil {
+			continue
+		}
+		p := Problem{Message: fmt.Sprintf("Kein Schreibzugriff auf %s. %s", dir, writeHint(dir)), Critical: true, Dir: dir}
+		if filepath.Base(dir) == "node_modules" {
+			// Installed modules still load, only npm install and rebuild fail
+			p.Message = fmt.Sprintf("Kein Schreibzugriff auf %s, npm install und npm rebuild werden fehlschlagen. %s", dir, writeHint(dir))
+			p.Critical = false
+		}
+		problems = append(problems, p)
+	}
+
+	if err := Writable(configDir); err != nil {
+		problems = append(problems, Problem{
+			Message: fmt.Sprintf("Kein Schreibzugriff auf den Konfigurationsordner %s, Profile und Einstellungen können nicht gespeichert werden. Einen beschreibbaren Ordner in app/.config_path eintragen oder mit --config-dir wählen", configDir),
+			Dir:     configDir,
+		})
+	}
+	return problems
+}
+
+// FreeSpace returns the bytes available to the current user on the volume
+// holding path. A path that does not exist yet is measured at its nearest
+// existing parent.
+func FreeSpace(path string) (uint64, error) {
+	return freeSpace(existingParent(path))
+}
+
+// Writable checks that files can be created in dir by creating and
+// removing one. A missing dir is checked at the nearest existing parent,
+// where it would be created.
+func Writable(dir string) error {
+	f, err := os.CreateTemp(existingParent(dir), ".ltth-write-test-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
+
+// Relocate prepares to as the new configuration directory: it is created
+// and must be writable. If to is empty and from is readable, the contents
+// of from are copied, so profiles and user data move along.
+func Relocate(from, to string) error {
+	if err := os.MkdirAll(to, 0755); err != nil {
+		return err
+	}
+	if err := Writable(to); err != nil {
+		return err
+	}
+	if entries, err := os.ReadDir(to); err != nil || len(entries) > 0 {
+		return err
+	}
+	if info, err := os.Stat(from); err != nil || !info.IsDir() {
+		return nil
+	}
+	err := filepath.WalkDir(from, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(from, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, rel)
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, 0755)
+		case d.Type().IsRegular():
+			return copyFile(p, target)
+		}
+		return nil
+	})
+	if err != nil {
+		// to was empty; a half copy would be taken as complete next time
+		os.RemoveAll(to)
+	}
+	return err
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	return errors.Join(err, out.Close())
+}
+
+func megabytes(n uint64) string {
+	if n >= 1<<30 {
+		return fmt.Sprintf("%.1f GB", float64(n)/(1<<30))
+	}
+	return fmt.Sprintf("%d MB", n>>20)
+}
+
+// within reports whether path is root or lies below it.
+func within(path, root string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// existingParent returns path or its nearest parent that exists.
+func existingParent(path string) string {
+	path = filepath.Clean(path)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
@@ -0,0 +1,231 @@
+package preflight
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// readOnly makes dir read-only (0555) for the rest of the test. Where that
+// is not enforced (Windows, root) the test is skipped.
+func readOnly(t *testing.T, dir string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("directory permissions are ACLs on Windows")
+	}
+	if err := os.Chmod(dir, 0555); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chmod(dir, 0755) })
+	if Writable(dir) == nil {
+		t.Skip("running as root, permissions are not enforced")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		dirs      []string // created below the temp dir
+		readOnly  []string
+		configDir string
+		want      []string // "dir critical" or "dir -" per problem
+	}{
+		{name: "writable", dirs: []string{"app/logs", "app/node_modules", "config"}, configDir: "config"},
+		{name: "missing config dir", dirs: []string{"app"}, configDir: "config/LTTH"},
+		{name: "read-only app", dirs: []string{"app/logs", "config"}, readOnly: []string{"app"}, configDir: "config", want: []string{"app critical"}},
+		{name: "read-only logs", dirs: []string{"app/logs", "config"}, readOnly: []string{"app/logs"}, configDir: "config", want: []string{"app/logs critical"}},
+		{name: "read-only node_modules", dirs: []string{"app/node_modules", "config"}, readOnly: []string{"app/node_modules"}, configDir: "config", want: []string{"app/node_modules -"}},
+		{name: "read-only config dir", dirs: []string{"app", "config"}, readOnly: []string{"config"}, configDir: "config", want: []string{"config -"}},
+		{name: "missing config dir in a read-only parent", dirs: []string{"app", "config"}, readOnly: []string{"config"}, configDir: "config/LTTH", want: []string{"config/LTTH -"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for _, d := range tt.dirs {
+				if err := os.MkdirAll(filepath.Join(root, d), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, d := range tt.readOnly {
+				readOnly(t, filepath.Join(root, d))
+			}
+			appDir := filepath.Join(root, "app")
+			if free, err := FreeSpace(appDir); err != nil || free < MinFree {
+				t.Skipf("only %d bytes free in the temp dir (%v)", free, err)
+			}
+
+			var got []string
+			for _, p := range Check(appDir, filepath.Join(root, tt.configDir), false) {
+				rel, err := filepath.Rel(root, p.Dir)
+				if p.Dir == "" || err != nil {
+					t.Fatalf("problem without directory: %s", p)
+				}
+				critical := "-"
+				if p.Critical {
+					critical = "critical"
+				}
+				got = append(got, filepath.ToSlash(rel)+" "+critical)
+				if !strings.Contains(p.Message, p.Dir) {
+					t.Errorf("message %q does not name %s", p.Message, p.Dir)
+				}
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.want, ", ") {
+				t.Errorf("Check() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// config is the content of a configuration directory.
+var config = map[string]string{
+	"settings.json":                        `{"lang":"de"}`,
+	"user_configs/.active_profile":         "streamer",
+	"user_configs/streamer.db":             "SQLite format 3",
+	"user_data/soundboard/sounds/ding.mp3": "ID3",
+}
+
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func readTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := map[string]string{}
+	filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			rel, _ := filepath.Rel(dir, p)
+			data, _ := os.ReadFile(p)
+			files[filepath.ToSlash(rel)] = string(data)
+		}
+		return nil
+	})
+	return files
+}
+
+func sameTree(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || w != v {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRelocate(t *testing.T) {
+	t.Run("copies into a missing target", func(t *testing.T) {
+		from, to := filepath.Join(t.TempDir(), "old"), filepath.Join(t.TempDir(), "new", "LTTH")
+		writeTree(t, from, config)
+		if err := Relocate(from, to); err != nil {
+			t.Fatal(err)
+		}
+		if got := readTree(t, to); !sameTree(got, config) {
+			t.Errorf("copied %v", got)
+		}
+		if got := readTree(t, from); !sameTree(got, config) {
+			t.Errorf("source changed: %v", got)
+		}
+	})
+
+	t.Run("copies into an empty target", func(t *testing.T) {
+		from, to := filepath.Join(t.TempDir(), "old"), t.TempDir()
+		writeTree(t, from, config)
+		if err := Relocate(from, to); err != nil {
+			t.Fatal(err)
+		}
+		if got := readTree(t, to); !sameTree(got, config) {
+			t.Errorf("copied %v", got)
+		}
+	})
+
+	t.Run("leaves a non-empty target alone", func(t *testing.T) {
+		from, to := filepath.Join(t.TempDir(), "old"), t.TempDir()
+		writeTree(t, from, config)
+		existing := map[string]string{"user_configs/other.db": "SQLite format 3"}
+		writeTree(t, to, existing)
+		if err := Relocate(from, to); err != nil {
+			t.Fatal(err)
+		}
+		if got := readTree(t, to); !sameTree(got, existing) {
+			t.Errorf("target changed: %v", got)
+		}
+	})
+
+	t.Run("missing source", func(t *testing.T) {
+		to := filepath.Join(t.TempDir(), "LTTH")
+		if err := Relocate(filepath.Join(t.TempDir(), "missing"), to); err != nil {
+			t.Fatal(err)
+		}
+		if info, err := os.Stat(to); err != nil || !info.IsDir() {
+			t.Errorf("target not created: %v", err)
+		}
+	})
+
+	t.Run("read-only target", func(t *testing.T) {
+		from, parent := filepath.Join(t.TempDir(), "old"), t.TempDir()
+		writeTree(t, from, config)
+		to := filepath.Join(parent, "LTTH")
+		os.Mkdir(to, 0755)
+		readOnly(t, to)
+		if err := Relocate(from, to); err == nil {
+			t.Error("Relocate() into a read-only directory succeeded")
+		}
+	})
+
+	t.Run("removes a half-done copy", func(t *testing.T) {
+		from, to := filepath.Join(t.TempDir(), "old"), filepath.Join(t.TempDir(), "LTTH")
+		writeTree(t, from, config)
+		// Unreadable, so the copy fails after other files were written
+		locked := filepath.Join(from, "user_data")
+		readOnly(t, locked)
+		if err := os.Chmod(locked, 0); err != nil {
+			t.Fatal(err)
+		}
+		if err := Relocate(from, to); err == nil {
+			t.Fatal("Relocate() with an unreadable source succeeded")
+		}
+		if _, err := os.Stat(to); !os.IsNotExist(err) {
+			t.Errorf("half-done copy left behind: %v", err)
+		}
+	})
+}
+
+func TestExistingParent(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "app", "logs"), 0755)
+	os.WriteFile(filepath.Join(root, "app", "launch.js"), nil, 0644)
+	tests := []struct {
+		path, want string
+	}{
+		{"app/logs", "app/logs"},
+		{"app/launch.js", "app/launch.js"},
+		{"app/node_modules", "app"},
+		{"app/node_modules/.bin/npm", "app"},
+		{"app/logs/../missing/x", "app"},
+		{"missing/deeper", "."},
+	}
+	for _, tt := range tests {
+		got := existingParent(filepath.Join(root, filepath.FromSlash(tt.path)))
+		if want := filepath.Join(root, filepath.FromSlash(tt.want)); got != want {
+			t.Errorf("existingParent(%s) = %s, want %s", tt.path, got, want)
+		}
+	}
+	// Nothing exists: the volume root is returned instead of looping
+	vol := filepath.VolumeName(root) + string(filepath.Separator)
+	if got := existingParent(filepath.Join(vol, "ltth-missing-root", "x")); got != vol {
+		t.Errorf("existingParent below a missing top-level directory = %s, want %s", got, vol)
+	}
+}
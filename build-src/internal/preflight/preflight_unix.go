@@ -0,0 +1,31 @@
+//go:build !windows
+
+package preflight
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+func freeSpace(path string) (uint64, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+	return uint64(st.Bavail) * uint64(st.Bsize), nil
+}
+
+// Protected reports whether dir lies in a system location that only root
+// can write to.
+func Protected(dir string) bool {
+	return within(dir, "/usr") || within(dir, "/opt")
+}
+
+// writeHint tells how to make dir writable.
+func writeHint(dir string) string {
+	if Protected(dir) {
+		return "LTTH liegt in einem Systemordner: in das Home-Verzeichnis verschieben oder als Benutzer installieren, der den Server startet"
+	}
+	return fmt.Sprintf("Rechte prüfen, z.B. sudo chown -R $USER %q", dir)
+}
@@ -0,0 +1,42 @@
+package preflight
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/windows"
+)
+
+func freeSpace(path string) (uint64, error) {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	var avail, total, free uint64
+	if err := windows.GetDiskFreeSpaceEx(p, &avail, &total, &free); err != nil {
+		return 0, err
+	}
+	return avail, nil
+}
+
+// Protected reports whether dir lies below Program Files, where only
+// administrators can write.
+func Protected(dir string) bool {
+	for _, env := range []string{"ProgramFiles", "ProgramFiles(x86)", "ProgramW6432"} {
+		if root := os.Getenv(env); root != "" && within(strings.ToLower(dir), strings.ToLower(root)) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeHint tells how to make dir writable.
+func writeHint(dir string) string {
+	home, _ := os.UserHomeDir()
+	if Protected(dir) {
+		return fmt.Sprintf("LTTH liegt unter \"Programme\", wo nur Administratoren schreiben dürfen: den LTTH-Ordner z.B. nach %s verschieben oder den Launcher als Administrator starten", filepath.Join(home, "LTTH"))
+	}
+	return "Schreibschutz und Ordnerrechte prüfen (Eigenschaften > Sicherheit) oder LTTH in einen eigenen Ordner verschieben"
+}
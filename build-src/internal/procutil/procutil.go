@@ -0,0 +1,32 @@
+// Package procutil wraps the platform specific bits of spawning, inspecting
+// and stopping child processes.
+package procutil
+
+import (
+	"time"
+)
+
+// Terminate asks the process to shut down gracefully and escalates to a hard
+// kill if it is still alive after timeout.
+func Terminate(pid int, timeout time.Duration) error {
+	if err := Interrupt(pid); err != nil && !Alive(pid) {
+		return nil
+	}
+	if WaitExit(pid, timeout) {
+		return nil
+	}
+	return Kill(pid)
+}
+
+// WaitExit polls until the process has exited or timeout elapses and reports
+// whether it exited.
+func WaitExit(pid int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if !Alive(pid) {
+			return true
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	return !Alive(pid)
+}
@@ -0,0 +1,54 @@
+//go:build !windows
+
+package procutil
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// HideWindow is a no-op outside Windows.
+func HideWindow(cmd *exec.Cmd) {}
+
+// AttachParentConsole is a no-op outside Windows; stdout is always usable.
+func AttachParentConsole() {}
+
+// NPM returns a command running npm with args.
+func NPM(args ...string) *exec.Cmd {
+	return exec.Command("npm", args...)
+}
+
+// Interrupt sends SIGINT, which the Node server handles as a graceful shutdown.
+func Interrupt(pid int) error {
+	return syscall.Kill(pid, syscall.SIGINT)
+}
+
+// Kill sends SIGKILL.
+func Kill(pid int) error {
+	return syscall.Kill(pid, syscall.SIGKILL)
+}
+
+// Alive reports whether a process with the given PID is still running.
+func Alive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
+// SetProcessGroup starts cmd in its own process group, so terminal signals
+// only reach the launcher and the whole server tree can be stopped at once.
+func SetProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// InterruptGroup sends SIGINT to the process group led by pid.
+func InterruptGroup(pid int) error {
+	return syscall.Kill(-pid, syscall.SIGINT)
+}
+
+// KillGroup sends SIGKILL to the process group led by pid.
+func KillGroup(pid int) error {
+	return syscall.Kill(-pid, syscall.SIGKILL)
+}
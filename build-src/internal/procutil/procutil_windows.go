@@ -0,0 +1,124 @@
+package procutil
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	// CREATE_NO_WINDOW flag for Windows to hide console window
+	createNoWindow = 0x08000000
+
+	processQueryLimitedInformation = 0x1000
+	stillActive                    = 259
+)
+
+var (
+	kernel32          = windows.NewLazySystemDLL("kernel32.dll")
+	procAttachConsole = kernel32.NewProc("AttachConsole")
+	procFreeConsole   = kernel32.NewProc("FreeConsole")
+)
+
+// HideWindow prevents cmd from opening a console window.
+func HideWindow(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= createNoWindow
+}
+
+// AttachParentConsole connects stdout/stderr of a GUI-subsystem build
+// (-H windowsgui) to the console it was started from, so command-line
+// subcommands can print their results.
+func AttachParentConsole() {
+	const attachParentProcess = ^uint32(0)
+	if r, _, _ := procAttachConsole.Call(uintptr(attachParentProcess)); r == 0 {
+		return
+	}
+	if out, err := os.OpenFile("CONOUT$", os.O_WRONLY, 0); err == nil {
+		os.Stdout = out
+		os.Stderr = out
+	}
+}
+
+// NPM returns a hidden command running npm (a batch file) through cmd with
+// args. Every argument is quoted so cmd leaves version ranges such as
+// "^1.2.0" or ">=2 <3" alone.
+func NPM(args ...string) *exec.Cmd {
+	quoted := []string{`"npm"`}
+	for _, a := range args {
+		quoted = append(quoted, `"`+strings.ReplaceAll(a, `"`, `""`)+`"`)
+	}
+	cmd := exec.Command("cmd")
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: `cmd /S /C "` + strings.Join(quoted, " ") + `"`}
+	HideWindow(cmd)
+	return cmd
+}
+
+// Interrupt asks the process tree to close without forcing it.
+func Interrupt(pid int) error {
+	cmd := exec.Command("taskkill", "/T", "/PID", strconv.Itoa(pid))
+	HideWindow(cmd)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("taskkill: %v: %s", err, out)
+	}
+	return nil
+}
+
+// Kill forcefully terminates the process tree.
+func Kill(pid int) error {
+	cmd := exec.Command("taskkill", "/F", "/T", "/PID", strconv.Itoa(pid))
+	HideWindow(cmd)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("taskkill /F: %v: %s", err, out)
+	}
+	return nil
+}
+
+// Alive reports whether a process with the given PID is still running.
+func Alive(pid int) bool {
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer syscall.CloseHandle(h)
+	var code uint32
+	if err := syscall.GetExitCodeProcess(h, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
+
+// SetProcessGroup starts cmd as the leader of a new console process group so
+// a CTRL_BREAK can be delivered to it without hitting the launcher.
+func SetProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= windows.CREATE_NEW_PROCESS_GROUP
+}
+
+// InterruptGroup sends CTRL_BREAK to the process group led by pid, which
+// Node.js reports as SIGBREAK. A GUI launcher has no console of its own, so
+// it briefly attaches to the child's console to deliver the event.
+func InterruptGroup(pid int) error {
+	attached, _, _ := procAttachConsole.Call(uintptr(pid))
+	if attached != 0 {
+		defer procFreeConsole.Call()
+	}
+	if err := windows.GenerateConsoleCtrlEvent(windows.CTRL_BREAK_EVENT, uint32(pid)); err != nil {
+		return fmt.Errorf("GenerateConsoleCtrlEvent: %v", err)
+	}
+	return nil
+}
+
+// KillGroup forcefully terminates pid and all of its children.
+func KillGroup(pid int) error {
+	return Kill(pid)
+}
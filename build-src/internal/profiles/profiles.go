@@ -0,0 +1,114 @@
+// Package profiles reads and switches the per-streamer profiles of the
+// Node.js server. It follows modules/user-profiles.js: every profile is a
+// SQLite database <name>.db in <config dir>/user_configs and the active one
+// is named in user_configs/.active_profile.
+package profiles
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// activeFile names the active profile inside the user_configs directory.
+const activeFile = ".active_profile"
+
+// namePattern is what /api/profiles/switch accepts.
+var namePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,50}$`)
+
+// Profile is one profile database.
+type Profile struct {
+	Name     string    `json:"name"`
+	LastUsed time.Time `json:"lastUsed"` // modification time of the database
+	Size     int64     `json:"size"`
+}
+
+// Dir returns the user_configs directory inside configDir.
+func Dir(configDir string) string {
+	return filepath.Join(configDir, "user_configs")
+}
+
+// DBPath returns the database file of a profile, sanitized the same way as
+// UserProfileManager.getProfilePath. JavaScript replaces UTF-16 code units,
+// so characters outside the BMP (emoji) become two underscores.
+func DBPath(configDir, name string) string {
+	var sanitized strings.Builder
+	for _, r := range name {
+		switch {
+		case r == '_' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9':
+			sanitized.WriteRune(r)
+		case r > 0xFFFF:
+			sanitized.WriteString("__")
+		default:
+			sanitized.WriteByte('_')
+		}
+	}
+	return filepath.Join(Dir(configDir), sanitized.String()+".db")
+}
+
+// ValidName reports whether name can be used as a profile name.
+func ValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// List returns all profiles, most recently used first. Copies written by
+// the dashboard's profile backup (<name>_backup_<time>.db) are skipped.
+func List(configDir string) ([]Profile, error) {
+	entries, err := os.ReadDir(Dir(configDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []Profile
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".db")
+		if !ok || e.IsDir() || strings.Contains(name, "_backup_") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		list = append(list, Profile{Name: name, LastUsed: info.ModTime(), Size: info.Size()})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].LastUsed.After(list[j].LastUsed) })
+	return list, nil
+}
+
+// Active returns the active profile, or "" if none is set or its database
+// no longer exists.
+func Active(configDir string) string {
+	data, err := os.ReadFile(filepath.Join(Dir(configDir), activeFile))
+	if err != nil {
+		return ""
+	}
+	// Like String.prototype.trim, which also strips a byte order mark
+	name := strings.TrimFunc(string(data), func(r rune) bool { return unicode.IsSpace(r) || r == '\uFEFF' })
+	if name == "" {
+		return ""
+	}
+	if _, err := os.Stat(DBPath(configDir, name)); err != nil {
+		return ""
+	}
+	return name
+}
+
+// SetActive makes name the active profile. The database does not have to
+// exist yet; the server creates it on startup.
+func SetActive(configDir, name string) error {
+	if !ValidName(name) {
+		return fmt.Errorf("ungültiger Profilname %q (erlaubt: a-z, A-Z, 0-9, _ und -)", name)
+	}
+	dir := Dir(configDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, activeFile), []byte(name), 0644)
+}
@@ -0,0 +1,126 @@
+package profiles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestDBPath pins the rules of UserProfileManager.getProfilePath:
+// username.replace(/[^a-zA-Z0-9_-]/g, '_') + '.db' in user_configs.
+func TestDBPath(t *testing.T) {
+	tests := []struct {
+		name, file string
+	}{
+		{"streamer_1", "streamer_1.db"},
+		{"-_-", "-_-.db"},
+		{"Max Mustermann", "Max_Mustermann.db"},
+		{"pupcid.live", "pupcid_live.db"},
+		{"ÄÖü", "___.db"},
+		{"a😀b", "a__b.db"}, // one emoji, two UTF-16 code units
+		{"../../etc/passwd", "______etc_passwd.db"},
+		{`C:\x`, "C__x.db"},
+		{"tab\there", "tab_here.db"},
+	}
+	configDir := filepath.Join("cfg", "LTTH")
+	for _, tt := range tests {
+		want := filepath.Join(configDir, "user_configs", tt.file)
+		if got := DBPath(configDir, tt.name); got != want {
+			t.Errorf("DBPath(%q) = %s, want %s", tt.name, got, want)
+		}
+	}
+}
+
+func TestValidName(t *testing.T) {
+	tests := []struct {
+		name string
+		ok   bool
+	}{
+		{"streamer_1", true},
+		{"Max-Mustermann", true},
+		{"Max Mustermann", false},
+		{"pupcid.live", false},
+		{"", false},
+		{"a😀b", false},
+		{"01234567890123456789012345678901234567890123456789", true},
+		{"012345678901234567890123456789012345678901234567890", false},
+	}
+	for _, tt := range tests {
+		if got := ValidName(tt.name); got != tt.ok {
+			t.Errorf("ValidName(%q) = %v, want %v", tt.name, got, tt.ok)
+		}
+	}
+}
+
+func TestActive(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string // of .active_profile, "-" for no file
+		dbs     []string
+		want    string
+	}{
+		{"no file", "-", []string{"streamer.db"}, ""},
+		{"empty file", "", []string{"streamer.db"}, ""},
+		{"plain name", "streamer", []string{"streamer.db"}, "streamer"},
+		{"trailing newline", "streamer\r\n", []string{"streamer.db"}, "streamer"},
+		{"surrounding blanks", "  streamer\t", []string{"streamer.db"}, "streamer"},
+		{"byte order mark", "\uFEFFstreamer", []string{"streamer.db"}, "streamer"},
+		{"database deleted", "streamer", []string{"other.db"}, ""},
+		{"name with space", "Max Mustermann", []string{"Max_Mustermann.db"}, "Max Mustermann"},
+	}
+	for _, tt := range tests {
+		configDir := t.TempDir()
+		dir := Dir(configDir)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		for _, db := range tt.dbs {
+			os.WriteFile(filepath.Join(dir, db), nil, 0644)
+		}
+		if tt.content != "-" {
+			os.WriteFile(filepath.Join(dir, activeFile), []byte(tt.content), 0644)
+		}
+		if got := Active(configDir); got != tt.want {
+			t.Errorf("%s: Active() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSetActive(t *testing.T) {
+	configDir := t.TempDir()
+	if err := SetActive(configDir, "../evil"); err == nil {
+		t.Error("SetActive accepted an invalid name")
+	}
+	if err := SetActive(configDir, "streamer"); err != nil {
+		t.Fatal(err)
+	}
+	// Written without a newline, like setActiveProfile does
+	data, err := os.ReadFile(filepath.Join(Dir(configDir), activeFile))
+	if err != nil || string(data) != "streamer" {
+		t.Errorf(".active_profile = %q, %v", data, err)
+	}
+	// Not active until the server created the database
+	if got := Active(configDir); got != "" {
+		t.Errorf("Active() without database = %q", got)
+	}
+	os.WriteFile(DBPath(configDir, "streamer"), nil, 0644)
+	if got := Active(configDir); got != "streamer" {
+		t.Errorf("Active() = %q, want streamer", got)
+	}
+}
+
+func TestListSkipsBackups(t *testing.T) {
+	configDir := t.TempDir()
+	dir := Dir(configDir)
+	os.MkdirAll(filepath.Join(dir, "folder.db"), 0755)
+	for _, f := range []string{"streamer.db", "streamer_backup_2025-01-02T20-00-00-000Z.db", "streamer.db-wal", activeFile} {
+		os.WriteFile(filepath.Join(dir, f), nil, 0644)
+	}
+	list, err := List(configDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Name != "streamer" {
+		t.Errorf("List() = %+v", list)
+	}
+}
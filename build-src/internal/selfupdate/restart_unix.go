@@ -0,0 +1,14 @@
+//go:build !windows
+
+package selfupdate
+
+import (
+	"os"
+	"syscall"
+)
+
+// Restart replaces the current process with exePath, keeping the PID so
+// systemd and launchd keep tracking it. It only returns on error.
+func Restart(exePath string) error {
+	return syscall.Exec(exePath, os.Args, os.Environ())
+}
@@ -0,0 +1,18 @@
+package selfupdate
+
+import (
+	"os"
+	"os/exec"
+)
+
+// Restart starts exePath with the current arguments and exits; Windows has
+// no exec. It only returns on error.
+func Restart(exePath string) error {
+	cmd := exec.Command(exePath, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	os.Exit(0)
+	return nil
+}
@@ -0,0 +1,268 @@
+// Package selfupdate replaces the launcher executable with a newer build.
+// A release is announced in a JSON manifest signed with ed25519; the binary
+// is downloaded next to the running executable as <exe>.new and swapped in
+// on the next start, after it passed a --selftest run at its final path.
+package selfupdate
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/procutil"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/semver"
+)
+
+// SelftestFlag makes the launcher print SelftestPrefix and its version and
+// exit without touching anything.
+const (
+	SelftestFlag   = "--selftest"
+	SelftestPrefix = "ltth-launcher "
+)
+
+// Suffixes of the files kept next to the executable.
+const (
+	newSuffix     = ".new"           // verified download waiting for the next start
+	pendingSuffix = ".update.json"   // version and checksum of the .new file
+	oldSuffix     = ".old"           // previous executable, removed on the start after
+	failedSuffix  = ".update-failed" // version that failed its selftest, not downloaded again
+)
+
+var (
+	manifestClient = &http.Client{Timeout: 30 * time.Second}
+	downloadClient = &http.Client{Timeout: 10 * time.Minute}
+)
+
+// File is the build for one platform.
+type File struct {
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// Manifest is the signed release announcement. Files are keyed by
+// GOOS-GOARCH, e.g. "windows-amd64".
+type Manifest struct {
+	Version string          `json:"version"`
+	Files   map[string]File `json:"files"`
+}
+
+// Release is the update for this platform.
+type Release struct {
+	Version string
+	File    File
+}
+
+// pending is stored in <exe>.update.json once the download is verified.
+type pending struct {
+	Version string `json:"version"`
+	SHA256  string `json:"sha256"`
+}
+
+// ParsePublicKey decodes a base64 ed25519 public key.
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, errors.New("ungültiger öffentlicher Schlüssel für Updates")
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// Check fetches manifestURL and manifestURL+".sig" (base64 signature of the
+// manifest bytes) and returns the release for this platform if it is newer
+// than current. Versions that failed their selftest here are skipped.
+func Check(exePath, manifestURL string, key ed25519.PublicKey, current string) (*Release, error) {
+	cur, err := semver.Parse(current)
+	if err != nil {
+		return nil, fmt.Errorf("Launcher-Version %q: %v", current, err)
+	}
+	data, err := fetch(manifestURL, 1<<20)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := fetch(manifestURL+".sig", 4<<10)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil || !ed25519.Verify(key, data, raw) {
+		return nil, errors.New("Signatur des Update-Manifests ist ungültig")
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("Update-Manifest: %v", err)
+	}
+	v, err := semver.Parse(m.Version)
+	if err != nil {
+		return nil, fmt.Errorf("Update-Manifest: %v", err)
+	}
+	if !cur.Less(v) {
+		return nil, nil
+	}
+	if failed, _ := os.ReadFile(exePath + failedSuffix); strings.TrimSpace(string(failed)) == m.Version {
+		return nil, nil
+	}
+	f, ok := m.Files[runtime.GOOS+"-"+runtime.GOARCH]
+	if !ok {
+		return nil, nil
+	}
+	if f.URL == "" || len(f.SHA256) != sha256.Size*2 || f.Size <= 0 {
+		return nil, fmt.Errorf("Update-Manifest: unvollständiger Eintrag für %s-%s", runtime.GOOS, runtime.GOARCH)
+	}
+	return &Release{Version: m.Version, File: f}, nil
+}
+
+// Download stores the release as <exe>.new after checking size and SHA-256.
+func Download(exePath string, r *Release) error {
+	resp, err := downloadClient.Get(r.File.URL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Download fehlgeschlagen: HTTP %d", resp.StatusCode)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(exePath), ".launcher-download-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmp, h), io.LimitReader(resp.Body, r.File.Size+1))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if n != r.File.Size {
+		return fmt.Errorf("Download hat %d statt %d Bytes", n, r.File.Size)
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(sum, r.File.SHA256) {
+		return fmt.Errorf("Prüfsumme stimmt nicht (%s statt %s)", sum, r.File.SHA256)
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), exePath+newSuffix); err != nil {
+		return err
+	}
+	state, err := json.Marshal(pending{Version: r.Version, SHA256: strings.ToLower(r.File.SHA256)})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(exePath+pendingSuffix, state, 0644)
+}
+
+// ApplyPending swaps a downloaded update in: the running executable is
+// renamed to <exe>.old, <exe>.new takes its place and must pass --selftest,
+// otherwise the old executable is restored. It returns the installed
+// version, "" if there was nothing to do. The caller restarts afterwards.
+func ApplyPending(exePath string) (string, error) {
+	data, err := os.ReadFile(exePath + pendingSuffix)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	// Whatever happens, the same download is not tried twice
+	defer os.Remove(exePath + pendingSuffix)
+
+	var p pending
+	if err := json.Unmarshal(data, &p); err != nil {
+		os.Remove(exePath + newSuffix)
+		return "", fmt.Errorf("%s: %v", filepath.Base(exePath+pendingSuffix), err)
+	}
+	newPath, oldPath := exePath+newSuffix, exePath+oldSuffix
+	if sum, err := fileSHA256(newPath); err != nil || sum != p.SHA256 {
+		os.Remove(newPath)
+		return "", fmt.Errorf("Update %s wurde seit dem Download verändert oder fehlt", p.Version)
+	}
+
+	os.Remove(oldPath)
+	if err := os.Rename(exePath, oldPath); err != nil {
+		return "", err
+	}
+	if err := os.Rename(newPath, exePath); err != nil {
+		os.Rename(oldPath, exePath)
+		return "", err
+	}
+	if err := Selftest(exePath, p.Version); err != nil {
+		// Roll back; remember the version so it is not downloaded again
+		os.Remove(exePath)
+		if rerr := os.Rename(oldPath, exePath); rerr != nil {
+			return "", fmt.Errorf("%v; Wiederherstellung fehlgeschlagen: %v", err, rerr)
+		}
+		os.WriteFile(exePath+failedSuffix, []byte(p.Version+"\n"), 0644)
+		return "", fmt.Errorf("Update %s besteht den Selbsttest nicht: %v", p.Version, err)
+	}
+	os.Remove(exePath + failedSuffix)
+	return p.Version, nil
+}
+
+// Cleanup removes the executable replaced by the last update. On Windows
+// this only works once the old process has exited; it is retried on the
+// next start.
+func Cleanup(exePath string) {
+	os.Remove(exePath + oldSuffix)
+}
+
+// Selftest runs path --selftest and checks that it reports version.
+func Selftest(path, version string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, path, SelftestFlag)
+	procutil.HideWindow(cmd)
+	out, err := cmd.Output()
+	if err != nil {
+		return err
+	}
+	got, ok := strings.CutPrefix(strings.TrimSpace(string(out)), SelftestPrefix)
+	if !ok || got != version {
+		return fmt.Errorf("meldet %q statt Version %s", bytes.TrimSpace(out), version)
+	}
+	return nil
+}
+
+func fetch(url string, limit int64) ([]byte, error) {
+	resp, err := manifestClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: HTTP %d", url, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, limit))
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
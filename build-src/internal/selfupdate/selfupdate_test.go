@@ -0,0 +1,285 @@
+package selfupdate
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// release serves a manifest, its signature and the binary for this
+// platform. sig overrides the signature ("-" for a missing .sig).
+type release struct {
+	version string
+	binary  []byte
+	sig     string
+	key     ed25519.PrivateKey
+}
+
+func (rel *release) serve(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	sum := sha256.Sum256(rel.binary)
+	manifest, err := json.Marshal(Manifest{
+		Version: rel.version,
+		Files: map[string]File{runtime.GOOS + "-" + runtime.GOARCH: {
+			URL:    srv.URL + "/launcher",
+			SHA256: hex.EncodeToString(sum[:]),
+			Size:   int64(len(rel.binary)),
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := rel.sig
+	if sig == "" {
+		sig = base64.StdEncoding.EncodeToString(ed25519.Sign(rel.key, manifest))
+	}
+	mux.HandleFunc("/manifest.json", func(w http.ResponseWriter, r *http.Request) { w.Write(manifest) })
+	mux.HandleFunc("/manifest.json.sig", func(w http.ResponseWriter, r *http.Request) {
+		if sig == "-" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(sig + "\n"))
+	})
+	mux.HandleFunc("/launcher", func(w http.ResponseWriter, r *http.Request) { w.Write(rel.binary) })
+	return srv
+}
+
+func newKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pub, priv
+}
+
+func TestCheck(t *testing.T) {
+	pub, priv := newKey(t)
+	_, otherKey := newKey(t)
+	tests := []struct {
+		name    string
+		version string
+		sig     string // "" signs with priv, "-" serves no .sig
+		signer  ed25519.PrivateKey
+		failed  string // content of <exe>.update-failed
+		want    string // version offered, "" for none
+		wantErr bool
+	}{
+		{name: "newer release", version: "1.3.0", want: "1.3.0"},
+		{name: "same version", version: "1.2.0"},
+		{name: "older version", version: "1.1.9"},
+		{name: "pre-release of the running version", version: "1.2.0-rc.1"},
+		{name: "failed its selftest before", version: "1.3.0", failed: "1.3.0\n"},
+		{name: "another version failed before", version: "1.3.1", failed: "1.3.0\n", want: "1.3.1"},
+		{name: "missing signature", version: "1.3.0", sig: "-", wantErr: true},
+		{name: "signature not base64", version: "1.3.0", sig: "not base64!", wantErr: true},
+		{name: "signed with another key", version: "1.3.0", signer: otherKey, wantErr: true},
+	}
+	for _, tt := range tests {
+		signer := priv
+		if tt.signer != nil {
+			signer = tt.signer
+		}
+		srv := (&release{version: tt.version, binary: []byte("binary"), sig: tt.sig, key: signer}).serve(t)
+		exe := filepath.Join(t.TempDir(), "launcher")
+		if tt.failed != "" {
+			os.WriteFile(exe+failedSuffix, []byte(tt.failed), 0644)
+		}
+
+		rel, err := Check(exe, srv.URL+"/manifest.json", pub, "1.2.0")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Check() error = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		got := ""
+		if rel != nil {
+			got = rel.Version
+		}
+		if got != tt.want {
+			t.Errorf("%s: Check() offered %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckRejectsTamperedManifest(t *testing.T) {
+	pub, priv := newKey(t)
+	srv := (&release{version: "1.3.0", binary: []byte("binary"), key: priv}).serve(t)
+	// Signature of a different manifest
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte(`{"version":"1.0.0"}`)))
+	srv2 := (&release{version: "1.3.0", binary: []byte("binary"), sig: sig, key: priv}).serve(t)
+
+	exe := filepath.Join(t.TempDir(), "launcher")
+	if _, err := Check(exe, srv.URL+"/manifest.json", pub, "1.2.0"); err != nil {
+		t.Fatalf("valid manifest: %v", err)
+	}
+	if _, err := Check(exe, srv2.URL+"/manifest.json", pub, "1.2.0"); err == nil {
+		t.Error("Check() accepted a signature over other bytes")
+	}
+}
+
+func TestDownload(t *testing.T) {
+	binary := []byte("#!/bin/sh\necho new launcher\n")
+	sum := sha256.Sum256(binary)
+	good := hex.EncodeToString(sum[:])
+	tests := []struct {
+		name    string
+		size    int64
+		sha256  string
+		wantErr bool
+	}{
+		{"verified", int64(len(binary)), good, false},
+		{"checksum in upper case", int64(len(binary)), strings.ToUpper(good), false},
+		{"shorter than announced", int64(len(binary)) + 1, good, true},
+		{"longer than announced", int64(len(binary)) - 1, good, true},
+		{"wrong checksum", int64(len(binary)), strings.Repeat("0", 64), true},
+	}
+	_, priv := newKey(t)
+	srv := (&release{version: "1.3.0", binary: binary, key: priv}).serve(t)
+	for _, tt := range tests {
+		dir := t.TempDir()
+		exe := filepath.Join(dir, "launcher")
+		rel := &Release{Version: "1.3.0", File: File{URL: srv.URL + "/launcher", SHA256: tt.sha256, Size: tt.size}}
+		err := Download(exe, rel)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Download() error = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		_, newErr := os.Stat(exe + newSuffix)
+		_, pendingErr := os.Stat(exe + pendingSuffix)
+		if tt.wantErr {
+			if newErr == nil || pendingErr == nil {
+				t.Errorf("%s: rejected download left %s or %s behind", tt.name, newSuffix, pendingSuffix)
+			}
+			if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+				t.Errorf("%s: temporary files left behind: %v", tt.name, entries)
+			}
+			continue
+		}
+		if data, _ := os.ReadFile(exe + newSuffix); string(data) != string(binary) {
+			t.Errorf("%s: %s = %q", tt.name, newSuffix, data)
+		}
+		if pendingErr != nil {
+			t.Errorf("%s: %v", tt.name, pendingErr)
+		}
+	}
+}
+
+// stubLauncher answers --selftest with the given version.
+func stubLauncher(version string) []byte {
+	return []byte("#!/bin/sh\n[ \"$1\" = --selftest ] && echo '" + SelftestPrefix + version + "'\n")
+}
+
+// installPending sets up a running launcher and a verified download of
+// binary announced as version.
+func installPending(t *testing.T, binary []byte, version string) string {
+	t.Helper()
+	exe := filepath.Join(t.TempDir(), "launcher")
+	if err := os.WriteFile(exe, stubLauncher("1.2.0"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	_, priv := newKey(t)
+	srv := (&release{version: version, binary: binary, key: priv}).serve(t)
+	sum := sha256.Sum256(binary)
+	rel := &Release{Version: version, File: File{URL: srv.URL + "/launcher", SHA256: hex.EncodeToString(sum[:]), Size: int64(len(binary))}}
+	if err := Download(exe, rel); err != nil {
+		t.Fatal(err)
+	}
+	return exe
+}
+
+func TestApplyPending(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the launcher stubs are shell scripts")
+	}
+	tests := []struct {
+		name      string
+		reports   string // version the new build prints for --selftest
+		tamper    bool   // the .new file changes after the download
+		installed string
+		wantErr   bool
+	}{
+		{name: "selftest passes", reports: "1.3.0", installed: "1.3.0"},
+		{name: "selftest reports another version", reports: "1.2.0", wantErr: true},
+		{name: "changed since the download", reports: "1.3.0", tamper: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		exe := installPending(t, stubLauncher(tt.reports), "1.3.0")
+		os.WriteFile(exe+failedSuffix, []byte("1.2.5\n"), 0644)
+		if tt.tamper {
+			os.WriteFile(exe+newSuffix, stubLauncher("6.6.6"), 0755)
+		}
+
+		installed, err := ApplyPending(exe)
+		if installed != tt.installed || (err != nil) != tt.wantErr {
+			t.Errorf("%s: ApplyPending() = %q, %v", tt.name, installed, err)
+		}
+		for _, suffix := range []string{newSuffix, pendingSuffix} {
+			if _, err := os.Stat(exe + suffix); err == nil {
+				t.Errorf("%s: %s left behind", tt.name, suffix)
+			}
+		}
+		data, _ := os.ReadFile(exe)
+		failed, _ := os.ReadFile(exe + failedSuffix)
+		switch {
+		case tt.installed != "":
+			if string(data) != string(stubLauncher(tt.reports)) {
+				t.Errorf("%s: new build not installed", tt.name)
+			}
+			if _, err := os.Stat(exe + oldSuffix); err != nil {
+				t.Errorf("%s: previous build not kept: %v", tt.name, err)
+			}
+			if len(failed) != 0 {
+				t.Errorf("%s: %s = %q after a passing selftest", tt.name, failedSuffix, failed)
+			}
+		case tt.tamper:
+			if string(data) != string(stubLauncher("1.2.0")) {
+				t.Errorf("%s: running build replaced", tt.name)
+			}
+			if string(failed) != "1.2.5\n" {
+				t.Errorf("%s: %s = %q", tt.name, failedSuffix, failed)
+			}
+		default:
+			// Rolled back
+			if string(data) != string(stubLauncher("1.2.0")) {
+				t.Errorf("%s: previous build not restored", tt.name)
+			}
+			if string(failed) != "1.3.0\n" {
+				t.Errorf("%s: %s = %q, want 1.3.0", tt.name, failedSuffix, failed)
+			}
+		}
+	}
+}
+
+func TestApplyPendingWithoutUpdate(t *testing.T) {
+	exe := filepath.Join(t.TempDir(), "launcher")
+	if installed, err := ApplyPending(exe); installed != "" || err != nil {
+		t.Errorf("ApplyPending() = %q, %v", installed, err)
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	pub, _ := newKey(t)
+	if key, err := ParsePublicKey(" " + base64.StdEncoding.EncodeToString(pub) + "\n"); err != nil || !key.Equal(pub) {
+		t.Errorf("ParsePublicKey() = %v, %v", key, err)
+	}
+	for _, s := range []string{"", "not base64!", base64.StdEncoding.EncodeToString(pub[:16])} {
+		if _, err := ParsePublicKey(s); err == nil {
+			t.Errorf("ParsePublicKey(%q) succeeded", s)
+		}
+	}
+}
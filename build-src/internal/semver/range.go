@@ -0,0 +1,240 @@
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Range is an npm version range such as "^1.2.0", "~2.1", ">=1.0.0 <3",
+// "1.2.x", "1.0.0 - 2.0.0" or "^1.0.0 || ^2.0.0".
+type Range struct {
+	sets [][]comparator // alternatives joined by "||"; an empty set matches everything
+}
+
+type comparator struct {
+	op string // "<", "<=", ">", ">=" or "="
+	v  Version
+}
+
+// partial is a version with missing or wildcard parts, e.g. "1.2" or "1.x".
+type partial struct {
+	v     Version
+	parts int // leading numeric parts, 0 for "*"
+}
+
+// ParseRange accepts the range syntax npm accepts in package.json. Tags,
+// URLs and paths ("latest", "github:user/repo", "file:../x") are errors.
+func ParseRange(s string) (Range, error) {
+	var r Range
+	for _, alt := range strings.Split(s, "||") {
+		set, err := parseSet(strings.TrimSpace(alt))
+		if err != nil {
+			return Range{}, fmt.Errorf("ungültiger Versionsbereich %q", s)
+		}
+		r.sets = append(r.sets, set)
+	}
+	return r, nil
+}
+
+// Contains reports whether v satisfies the range. Like npm, a pre-release
+// only matches if a comparator of the same alternative names a pre-release
+// of the same major.minor.patch.
+func (r Range) Contains(v Version) bool {
+	for _, set := range r.sets {
+		if matchSet(set, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSet(set []comparator, v Version) bool {
+	for _, c := range set {
+		if !c.match(v) {
+			return false
+		}
+	}
+	if v.Pre == "" {
+		return true
+	}
+	for _, c := range set {
+		if c.v.Pre != "" && c.v.Major == v.Major && c.v.Minor == v.Minor && c.v.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+func (c comparator) match(v Version) bool {
+	d := v.Compare(c.v)
+	switch c.op {
+	case "<":
+		return d < 0
+	case "<=":
+		return d <= 0
+	case ">":
+		return d > 0
+	case ">=":
+		return d >= 0
+	}
+	return d == 0
+}
+
+func parseSet(s string) ([]comparator, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 3 && fields[1] == "-" {
+		return parseHyphen(fields[0], fields[2])
+	}
+	var set []comparator
+	for i := 0; i < len(fields); i++ {
+		tok := fields[i]
+		// npm allows a space between operator and version: ">= 1.2.0"
+		if strings.Trim(tok, "<>=^~") == "" && i+1 < len(fields) {
+			i++
+			tok += fields[i]
+		}
+		cs, err := parseComparator(tok)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, cs...)
+	}
+	return set, nil
+}
+
+func parseComparator(tok string) ([]comparator, error) {
+	op := ""
+	for _, prefix := range []string{">=", "<=", "~>", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(tok, prefix) {
+			op, tok = prefix, tok[len(prefix):]
+			break
+		}
+	}
+	p, err := parsePartial(tok)
+	if err != nil {
+		return nil, err
+	}
+	lo := p.v
+	switch op {
+	case "^":
+		if p.parts == 0 {
+			return nil, nil
+		}
+		hi := Version{Major: p.v.Major + 1, Pre: "0"}
+		switch {
+		case p.v.Major > 0 || p.parts == 1:
+		case p.v.Minor > 0 || p.parts == 2:
+			hi = Version{Minor: p.v.Minor + 1, Pre: "0"}
+		default:
+			hi = Version{Patch: p.v.Patch + 1, Pre: "0"}
+		}
+		return []comparator{{">=", lo}, {"<", hi}}, nil
+	case "~", "~>":
+		if p.parts == 0 {
+			return nil, nil
+		}
+		hi := Version{Major: p.v.Major + 1, Pre: "0"}
+		if p.parts > 1 {
+			hi = Version{Major: p.v.Major, Minor: p.v.Minor + 1, Pre: "0"}
+		}
+		return []comparator{{">=", lo}, {"<", hi}}, nil
+	case "", "=":
+		switch p.parts {
+		case 0:
+			return nil, nil
+		case 3:
+			return []comparator{{"=", lo}}, nil
+		}
+		return []comparator{{">=", lo}, {"<", p.next()}}, nil
+	}
+
+	if p.parts == 3 {
+		return []comparator{{op, lo}}, nil
+	}
+	if p.parts == 0 {
+		if op == ">=" || op == "<=" {
+			return nil, nil
+		}
+		// ">*" and "<*" match nothing
+		return []comparator{{"<", Version{Pre: "0"}}}, nil
+	}
+	switch op {
+	case ">":
+		hi := p.next()
+		hi.Pre = ""
+		return []comparator{{">=", hi}}, nil
+	case "<=":
+		return []comparator{{"<", p.next()}}, nil
+	case "<":
+		lo.Pre = "0"
+		return []comparator{{"<", lo}}, nil
+	}
+	return []comparator{{">=", lo}}, nil
+}
+
+func parseHyphen(from, to string) ([]comparator, error) {
+	lo, err := parsePartial(from)
+	if err != nil {
+		return nil, err
+	}
+	hi, err := parsePartial(to)
+	if err != nil {
+		return nil, err
+	}
+	var set []comparator
+	if lo.parts > 0 {
+		set = append(set, comparator{">=", lo.v})
+	}
+	switch {
+	case hi.parts == 3:
+		set = append(set, comparator{"<=", hi.v})
+	case hi.parts > 0:
+		set = append(set, comparator{"<", hi.next()})
+	}
+	return set, nil
+}
+
+// parsePartial reads "1", "1.2", "1.2.3-beta", "1.x", "1.2.*" or "*";
+// everything after the first wildcard is ignored.
+func parsePartial(s string) (partial, error) {
+	var p partial
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "="), "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	if s == "" {
+		return p, nil
+	}
+	core := s
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		core, p.v.Pre = s[:i], s[i+1:]
+	}
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		return p, fmt.Errorf("ungültige Version %q", s)
+	}
+	for i, dst := range []*int{&p.v.Major, &p.v.Minor, &p.v.Patch}[:len(parts)] {
+		if parts[i] == "x" || parts[i] == "X" || parts[i] == "*" {
+			break
+		}
+		n, err := strconv.Atoi(parts[i])
+		if err != nil || n < 0 {
+			return p, fmt.Errorf("ungültige Version %q", s)
+		}
+		*dst = n
+		p.parts++
+	}
+	if p.v.Pre != "" && p.parts < 3 {
+		return p, fmt.Errorf("ungültige Version %q", s)
+	}
+	return p, nil
+}
+
+// next is the exclusive upper bound of a partial version: "1.2" -> 1.3.0-0.
+func (p partial) next() Version {
+	if p.parts == 1 {
+		return Version{Major: p.v.Major + 1, Pre: "0"}
+	}
+	return Version{Major: p.v.Major, Minor: p.v.Minor + 1, Pre: "0"}
+}
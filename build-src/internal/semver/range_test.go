@@ -0,0 +1,118 @@
+package semver
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRangeContains(t *testing.T) {
+	tests := []struct {
+		rng     string
+		in, out string // space separated versions
+	}{
+		// Caret
+		{"^1.2.3", "1.2.3 1.2.4 1.9.0", "1.2.2 2.0.0 2.0.0-0 1.3.0-beta.1"},
+		{"^0.2.3", "0.2.3 0.2.9", "0.3.0 0.2.2 1.0.0"},
+		{"^0.0.3", "0.0.3", "0.0.4 0.1.0"},
+		{"^1.2", "1.2.0 1.9.9", "1.1.9 2.0.0"},
+		{"^0.x", "0.0.0 0.9.9", "1.0.0"},
+		{"^1.2.3-beta.2", "1.2.3-beta.2 1.2.3-beta.10 1.2.3 1.5.0", "1.2.3-beta.1 1.2.4-beta.1 2.0.0"},
+		// Tilde
+		{"~1.2.3", "1.2.3 1.2.9", "1.3.0 1.2.2"},
+		{"~1.2", "1.2.0 1.2.9", "1.3.0"},
+		{"~1", "1.0.0 1.9.9", "2.0.0 0.9.9"},
+		{"~>2.1.0", "2.1.5", "2.2.0"},
+		// Comparators
+		{">=1.0.0", "1.0.0 99.0.0", "0.9.9 1.0.0-rc.1"},
+		{">= 1.2.0", "1.2.0", "1.1.9"},
+		{">1.2", "1.3.0", "1.2.9"},
+		{"<=1.2", "1.2.9", "1.3.0"},
+		{"<2", "1.9.9", "2.0.0 2.0.0-0"},
+		{"=1.2.3", "1.2.3", "1.2.4"},
+		{"1.2.3", "1.2.3 v1.2.3+build", "1.2.4"},
+		{">1.0.0 <=1.5.0", "1.0.1 1.5.0", "1.0.0 1.5.1"},
+		// Wildcards
+		{"*", "0.0.0 5.1.2", "1.0.0-alpha"},
+		{"", "1.0.0", ""},
+		{"1.x", "1.0.0 1.9.9", "2.0.0"},
+		{"1.2.*", "1.2.0 1.2.9", "1.3.0"},
+		{"X", "3.0.0", ""},
+		// Hyphen
+		{"1.2.3 - 2.3.4", "1.2.3 2.3.4", "1.2.2 2.3.5"},
+		{"1.2 - 2.3", "1.2.0 2.3.9", "2.4.0 1.1.9"},
+		// Alternatives
+		{"^1.0.0 || ^2.0.0", "1.5.0 2.5.0", "3.0.0 0.9.0"},
+		{"<1.0.0 || >=2.0.0", "0.5.0 2.0.0", "1.5.0"},
+		{"1.2.3-alpha.1 || >=2", "1.2.3-alpha.1 2.0.0", "1.2.3-alpha.2"},
+		// Pre-releases only match their own major.minor.patch
+		{">1.2.3-alpha.3", "1.2.3-alpha.7 3.4.5", "3.4.5-alpha.9 1.2.3-alpha.2"},
+		// Ranges from app/package.json
+		{"^0.0.6", "0.0.6", "0.0.7 0.1.0"},
+		{"^11.9.0", "11.9.0 11.10.1", "12.0.0 11.8.9"},
+		{"^3.0.0-alpha.6", "3.0.0-alpha.6 3.0.0-beta.1 3.0.0 3.1.0", "3.0.0-alpha.5 3.1.0-alpha.1 4.0.0"},
+		{">=18.0.0 <25.0.0", "18.0.0 20.11.1 24.9.0", "17.9.9 25.0.0 25.0.0-pre 22.0.0-nightly"},
+	}
+	for _, tt := range tests {
+		r, err := ParseRange(tt.rng)
+		if err != nil {
+			t.Errorf("ParseRange(%q): %v", tt.rng, err)
+			continue
+		}
+		for _, s := range strings.Fields(tt.in) {
+			if !r.Contains(mustParse(t, s)) {
+				t.Errorf("%q should contain %s", tt.rng, s)
+			}
+		}
+		for _, s := range strings.Fields(tt.out) {
+			if r.Contains(mustParse(t, s)) {
+				t.Errorf("%q should not contain %s", tt.rng, s)
+			}
+		}
+	}
+}
+
+func TestParseRangeErrors(t *testing.T) {
+	for _, s := range []string{"latest", "github:user/repo", "file:../x", "1.2.3.4", "^1.x-beta", "1.2.3 ||| 2", ">=a"} {
+		if _, err := ParseRange(s); err == nil {
+			t.Errorf("ParseRange(%q) succeeded", s)
+		}
+	}
+}
+
+// TestPackageJSONRanges parses every range of the real app/package.json,
+// the launcher checks node_modules and the Node.js version against them.
+func TestPackageJSONRanges(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "..", "app", "package.json"))
+	if err != nil {
+		t.Skip("app/package.json not found:", err)
+	}
+	var pkg map[string]json.RawMessage
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"dependencies", "optionalDependencies", "devDependencies", "engines"} {
+		var ranges map[string]string
+		if raw, ok := pkg[field]; ok {
+			if err := json.Unmarshal(raw, &ranges); err != nil {
+				t.Fatalf("%s: %v", field, err)
+			}
+		}
+		for name, rng := range ranges {
+			if _, err := ParseRange(rng); err != nil {
+				t.Errorf("%s %s: %v", field, name, err)
+			}
+		}
+	}
+}
+
+func mustParse(t *testing.T, s string) Version {
+	t.Helper()
+	v, err := Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
@@ -0,0 +1,107 @@
+// Package semver parses and orders SemVer 2.0 versions as they appear in
+// package.json and CHANGELOG.md.
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed version; build metadata is dropped, it does not
+// affect the order.
+type Version struct {
+	Major, Minor, Patch int
+	Pre                 string // pre-release, e.g. "beta.2"
+}
+
+// Parse accepts "1.2.3", "v1.2.3", "1.2.3-beta.2" and "1.2.3+build".
+func Parse(s string) (Version, error) {
+	var v Version
+	core := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(core, '+'); i >= 0 {
+		core = core[:i]
+	}
+	if i := strings.IndexByte(core, '-'); i >= 0 {
+		core, v.Pre = core[:i], core[i+1:]
+		if v.Pre == "" {
+			return Version{}, fmt.Errorf("ungültige Version %q", s)
+		}
+	}
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("ungültige Version %q", s)
+	}
+	for i, dst := range []*int{&v.Major, &v.Minor, &v.Patch} {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("ungültige Version %q", s)
+		}
+		*dst = n
+	}
+	return v, nil
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 as v is older than, equal to or newer than o.
+func (v Version) Compare(o Version) int {
+	for _, d := range [][2]int{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}} {
+		if d[0] != d[1] {
+			return cmp(d[0], d[1])
+		}
+	}
+	// A pre-release is older than the release itself
+	switch {
+	case v.Pre == o.Pre:
+		return 0
+	case v.Pre == "":
+		return 1
+	case o.Pre == "":
+		return -1
+	}
+	a, b := strings.Split(v.Pre, "."), strings.Split(o.Pre, ".")
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := comparePre(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return cmp(len(a), len(b))
+}
+
+// Less reports whether v is older than o.
+func (v Version) Less(o Version) bool {
+	return v.Compare(o) < 0
+}
+
+// comparePre orders one pre-release identifier: numbers numerically and
+// before text, text lexically.
+func comparePre(a, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return cmp(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func cmp(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
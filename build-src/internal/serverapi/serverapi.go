@@ -0,0 +1,137 @@
+// Package serverapi queries the HTTP API of the running LTTH Node.js server.
+package serverapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ErrRateLimited is returned when the server's API rate limiter rejected
+// the request. The server is alive, its state is just unknown.
+var ErrRateLimited = errors.New("rate limited")
+
+var client = &http.Client{Timeout: 3 * time.Second}
+
+// Status is the part of GET /api/status the launcher cares about.
+type Status struct {
+	IsConnected bool   `json:"isConnected"` // connected to a TikTok LIVE
+	Username    string `json:"username"`
+}
+
+// GetStatus asks the server on localhost:port whether it is connected to a
+// TikTok LIVE.
+func GetStatus(port int) (Status, error) {
+	var st Status
+	err := get(port, "/api/status", &st)
+	return st, err
+}
+
+// Ping requests path and returns how long the server took to answer. Any
+// status other than 200 is an error.
+func Ping(port int, path string, timeout time.Duration) (time.Duration, error) {
+	c := &http.Client{Timeout: timeout}
+	start := time.Now()
+	resp, err := c.Get(fmt.Sprintf("http://localhost:%d%s", port, path))
+	if err != nil {
+		return time.Since(start), err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	latency := time.Since(start)
+	if resp.StatusCode != http.StatusOK {
+		return latency, fmt.Errorf("%s: HTTP %d", path, resp.StatusCode)
+	}
+	return latency, nil
+}
+
+// InitState is the part of GET /api/init-state that tells whether the
+// plugins are loaded.
+type InitState struct {
+	PluginsLoaded    bool `json:"pluginsLoaded"`
+	PluginInjections bool `json:"pluginInjections"` // plugin services handed to the IFTTT engine, the last plugin step
+	Errors           []struct {
+		Component string `json:"component"`
+		Message   string `json:"message"`
+	} `json:"errors"`
+}
+
+// PluginsReady reports whether the server finished loading its plugins,
+// successfully or not.
+func (s InitState) PluginsReady() bool {
+	if s.PluginsLoaded && s.PluginInjections {
+		return true
+	}
+	for _, e := range s.Errors {
+		if e.Component == "plugin-loader" {
+			return true
+		}
+	}
+	return false
+}
+
+// GetInitState asks the server how far its initialization got.
+func GetInitState(port int) (InitState, error) {
+	var st InitState
+	err := get(port, "/api/init-state", &st)
+	return st, err
+}
+
+// ConnectionHealth returns the TikTok connection diagnostics of
+// GET /api/connection-health as raw JSON.
+func ConnectionHealth(port int) (json.RawMessage, error) {
+	var raw json.RawMessage
+	err := get(port, "/api/connection-health", &raw)
+	return raw, err
+}
+
+// SetPluginEnabled loads or unloads a plugin in the running server through
+// POST /api/plugins/:id/enable|disable, which also persists the state.
+func SetPluginEnabled(port int, id string, on bool) error {
+	action := "disable"
+	if on {
+		action = "enable"
+	}
+	path := fmt.Sprintf("/api/plugins/%s/%s", url.PathEscape(id), action)
+	// Enabling loads and initializes the plugin, which can take a while
+	c := &http.Client{Timeout: 30 * time.Second}
+	resp, err := c.Post(fmt.Sprintf("http://localhost:%d%s", port, path), "application/json", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Success bool   `json:"success"`
+		Error   string `json:"error"`
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case json.NewDecoder(resp.Body).Decode(&result) != nil:
+		return fmt.Errorf("%s: HTTP %d", path, resp.StatusCode)
+	case !result.Success:
+		return errors.New(result.Error)
+	}
+	return nil
+}
+
+func get(port int, path string, v interface{}) error {
+	resp, err := client.Get(fmt.Sprintf("http://localhost:%d%s", port, path))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("%s: HTTP %d", path, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
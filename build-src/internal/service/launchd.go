@@ -0,0 +1,137 @@
+package service
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// launchdLabel turns the service name into a reverse-DNS launchd label.
+func launchdLabel(name string) string {
+	return "com.loggableim." + name
+}
+
+// LaunchAgentsDir returns ~/Library/LaunchAgents.
+func LaunchAgentsDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Library", "LaunchAgents"), nil
+}
+
+// RenderLaunchdPlist returns a launchd agent definition. KeepAlive with
+// SuccessfulExit=false restarts the launcher after crashes only, like
+// Restart=on-failure under systemd.
+func RenderLaunchdPlist(s Spec) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	b.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	b.WriteString(`<plist version="1.0">` + "\n<dict>\n")
+	plistString(&b, "Label", launchdLabel(s.Name))
+	b.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, a := range append([]string{s.Exe}, s.Args...) {
+		fmt.Fprintf(&b, "\t\t<string>%s</string>\n", xmlEscape(a))
+	}
+	b.WriteString("\t</array>\n")
+	plistString(&b, "WorkingDirectory", s.WorkDir)
+
+	if len(s.Env) > 0 {
+		keys := make([]string, 0, len(s.Env))
+		for k := range s.Env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.WriteString("\t<key>EnvironmentVariables</key>\n\t<dict>\n")
+		for _, k := range keys {
+			fmt.Fprintf(&b, "\t\t<key>%s</key>\n\t\t<string>%s</string>\n", xmlEscape(k), xmlEscape(s.Env[k]))
+		}
+		b.WriteString("\t</dict>\n")
+	}
+
+	b.WriteString("\t<key>RunAtLoad</key>\n\t<true/>\n")
+	b.WriteString("\t<key>KeepAlive</key>\n\t<dict>\n\t\t<key>SuccessfulExit</key>\n\t\t<false/>\n\t</dict>\n")
+	if s.StopTimeout > 0 {
+		fmt.Fprintf(&b, "\t<key>ExitTimeOut</key>\n\t<integer>%d</integer>\n", int(s.StopTimeout.Seconds())+1)
+	}
+	b.WriteString("</dict>\n</plist>\n")
+	return b.String()
+}
+
+func plistString(b *strings.Builder, key, value string) {
+	fmt.Fprintf(b, "\t<key>%s</key>\n\t<string>%s</string>\n", key, xmlEscape(value))
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func launchdPlistPath(name string, opts Options) (string, error) {
+	dir := opts.Dir
+	if dir == "" {
+		var err error
+		if dir, err = LaunchAgentsDir(); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(dir, launchdLabel(name)+".plist"), nil
+}
+
+func launchdInstall(s Spec, opts Options) (string, error) {
+	path, err := launchdPlistPath(s.Name, opts)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(RenderLaunchdPlist(s)), 0644); err != nil || opts.DryRun {
+		return path, err
+	}
+	// Reload so a changed plist takes effect
+	run("launchctl", "unload", path)
+	_, err = run("launchctl", "load", "-w", path)
+	return path, err
+}
+
+func launchdUninstall(name string, opts Options) error {
+	path, err := launchdPlistPath(name, opts)
+	if err != nil {
+		return err
+	}
+	if !opts.DryRun {
+		run("launchctl", "unload", "-w", path)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func launchdQuery(name string, opts Options) (Status, error) {
+	path, err := launchdPlistPath(name, opts)
+	if err != nil {
+		return Status{}, err
+	}
+	st := Status{Path: path}
+	if _, err := os.Stat(path); err == nil {
+		st.Installed = true
+		st.Enabled = true
+	}
+	if opts.DryRun {
+		return st, nil
+	}
+	out, err := run("launchctl", "list", launchdLabel(name))
+	if err != nil {
+		st.Detail = "nicht geladen"
+		return st, nil
+	}
+	st.Running = strings.Contains(out, `"PID" = `)
+	st.Detail = "geladen"
+	return st, nil
+}
@@ -0,0 +1,43 @@
+package service
+
+import "strings"
+
+// runKeyPath is the per-user autostart key used on Windows.
+const runKeyPath = `Software\Microsoft\Windows\CurrentVersion\Run`
+
+// RenderRunCommand returns the command line stored in the Run key.
+func RenderRunCommand(s Spec) string {
+	parts := []string{windowsQuote(s.Exe)}
+	for _, a := range s.Args {
+		parts = append(parts, windowsQuote(a))
+	}
+	return strings.Join(parts, " ")
+}
+
+// windowsQuote quotes an argument the way CommandLineToArgvW parses it:
+// backslashes are only special in front of a quote.
+func windowsQuote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\"") {
+		return s
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	slashes := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '\\':
+			slashes++
+		case '"':
+			// Double the backslashes written so far and escape the quote
+			b.WriteString(strings.Repeat(`\`, slashes+1))
+			slashes = 0
+		default:
+			slashes = 0
+		}
+		b.WriteByte(c)
+	}
+	b.WriteString(strings.Repeat(`\`, slashes))
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,59 @@
+// Package service registers the launcher to start automatically in headless
+// mode: a systemd user unit on Linux, a launchd agent on macOS and a Run
+// registry entry on Windows (the same mechanism app/modules/auto-start.js
+// uses, so no administrator rights are needed).
+//
+// The file renderers are platform independent so they can be checked on any
+// system; Install, Uninstall and Query only exist for the current platform.
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DefaultName is the unit, agent or registry value name.
+const DefaultName = "ltth"
+
+// Spec describes how the service starts the launcher.
+type Spec struct {
+	Name        string
+	Description string
+	Exe         string   // absolute path of the launcher executable
+	Args        []string // launcher arguments, normally starting with --headless
+	WorkDir     string
+	Env         map[string]string
+	StopTimeout time.Duration // how long the service manager waits for a clean stop
+}
+
+// Options controls where service files are written.
+type Options struct {
+	Dir    string // unit/agent directory; empty: the platform default
+	DryRun bool   // only write the file, never call systemctl/launchctl
+}
+
+// Status is the result of Query.
+type Status struct {
+	Installed bool
+	Path      string // unit or plist file, registry key on Windows
+	Enabled   bool
+	Running   bool
+	Detail    string // raw state reported by the service manager
+}
+
+// run executes a service manager command and includes its output in errors.
+func run(name string, args ...string) (string, error) {
+	var out bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	text := strings.TrimSpace(out.String())
+	if err != nil {
+		return text, fmt.Errorf("%s %s: %v: %s", name, strings.Join(args, " "), err, text)
+	}
+	return text, nil
+}
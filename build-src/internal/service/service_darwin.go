@@ -0,0 +1,16 @@
+package service
+
+// Install writes the launchd agent, loads it and returns the plist path.
+func Install(s Spec, opts Options) (string, error) {
+	return launchdInstall(s, opts)
+}
+
+// Uninstall unloads the agent and removes the plist.
+func Uninstall(name string, opts Options) error {
+	return launchdUninstall(name, opts)
+}
+
+// Query reports whether the agent is installed and running.
+func Query(name string, opts Options) (Status, error) {
+	return launchdQuery(name, opts)
+}
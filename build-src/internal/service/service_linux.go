@@ -0,0 +1,17 @@
+package service
+
+// Install writes the systemd user unit, enables and starts it, and returns
+// the unit file path.
+func Install(s Spec, opts Options) (string, error) {
+	return systemdInstall(s, opts)
+}
+
+// Uninstall stops and disables the unit and removes the unit file.
+func Uninstall(name string, opts Options) error {
+	return systemdUninstall(name, opts)
+}
+
+// Query reports whether the unit is installed, enabled and running.
+func Query(name string, opts Options) (Status, error) {
+	return systemdQuery(name, opts)
+}
@@ -0,0 +1,25 @@
+//go:build !linux && !darwin && !windows
+
+package service
+
+import (
+	"fmt"
+	"runtime"
+)
+
+var errUnsupported = fmt.Errorf("Dienst-Installation wird auf %s nicht unterstützt", runtime.GOOS)
+
+// Install is not supported on this platform.
+func Install(s Spec, opts Options) (string, error) {
+	return "", errUnsupported
+}
+
+// Uninstall is not supported on this platform.
+func Uninstall(name string, opts Options) error {
+	return errUnsupported
+}
+
+// Query is not supported on this platform.
+func Query(name string, opts Options) (Status, error) {
+	return Status{}, errUnsupported
+}
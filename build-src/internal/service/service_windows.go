@@ -0,0 +1,63 @@
+package service
+
+import (
+	"errors"
+	"os/exec"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// Install adds the launcher to the current user's Run key and starts it.
+// opts.Dir is ignored; the returned path names the registry value.
+func Install(s Spec, opts Options) (string, error) {
+	k, _, err := registry.CreateKey(registry.CURRENT_USER, runKeyPath, registry.SET_VALUE)
+	if err != nil {
+		return "", err
+	}
+	defer k.Close()
+	if err := k.SetStringValue(s.Name, RenderRunCommand(s)); err != nil {
+		return "", err
+	}
+	path := `HKCU\` + runKeyPath + `\` + s.Name
+	if opts.DryRun {
+		return path, nil
+	}
+
+	// Start now as well, like systemctl enable --now
+	cmd := exec.Command(s.Exe, s.Args...)
+	cmd.Dir = s.WorkDir
+	if err := cmd.Start(); err != nil {
+		return path, err
+	}
+	return path, cmd.Process.Release()
+}
+
+// Uninstall removes the Run entry. A running launcher keeps running.
+func Uninstall(name string, opts Options) error {
+	k, err := registry.OpenKey(registry.CURRENT_USER, runKeyPath, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer k.Close()
+	if err := k.DeleteValue(name); err != nil && !errors.Is(err, registry.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Query reports whether the Run entry exists. Whether the launcher is
+// running is known from its lock file, not from Windows.
+func Query(name string, opts Options) (Status, error) {
+	st := Status{Path: `HKCU\` + runKeyPath + `\` + name}
+	k, err := registry.OpenKey(registry.CURRENT_USER, runKeyPath, registry.QUERY_VALUE)
+	if err != nil {
+		return st, nil
+	}
+	defer k.Close()
+	if cmdline, _, err := k.GetStringValue(name); err == nil {
+		st.Installed = true
+		st.Enabled = true
+		st.Detail = cmdline
+	}
+	return st, nil
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SystemdUserDir returns the directory for systemd user units.
+func SystemdUserDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "systemd", "user"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "systemd", "user"), nil
+}
+
+// RenderSystemdUnit returns the content of a systemd user unit. systemd
+// sends SIGTERM to the launcher only (KillMode=mixed); the launcher stops
+// the server's process group itself and exits 0, so Restart=on-failure
+// only restarts after crashes.
+func RenderSystemdUnit(s Spec) string {
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	fmt.Fprintf(&b, "Description=%s\n", s.Description)
+	b.WriteString("\n[Service]\n")
+	b.WriteString("Type=simple\n")
+	fmt.Fprintf(&b, "WorkingDirectory=%s\n", systemdQuote(s.WorkDir))
+	args := []string{systemdQuote(s.Exe)}
+	for _, a := range s.Args {
+		args = append(args, systemdQuote(a))
+	}
+	fmt.Fprintf(&b, "ExecStart=%s\n", strings.Join(args, " "))
+
+	keys := make([]string, 0, len(s.Env))
+	for k := range s.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "Environment=%s\n", systemdQuote(k+"="+s.Env[k]))
+	}
+
+	b.WriteString("Restart=on-failure\n")
+	b.WriteString("RestartSec=5\n")
+	b.WriteString("KillMode=mixed\n")
+	if s.StopTimeout > 0 {
+		fmt.Fprintf(&b, "TimeoutStopSec=%d\n", int(math.Ceil(s.StopTimeout.Seconds())))
+	}
+	b.WriteString("\n[Install]\n")
+	b.WriteString("WantedBy=default.target\n")
+	return b.String()
+}
+
+// systemdQuote quotes a word for unit files: specifiers (%) and variable
+// references ($) are escaped so paths are taken literally.
+func systemdQuote(s string) string {
+	s = strings.ReplaceAll(s, "%", "%%")
+	s = strings.ReplaceAll(s, "$", "$$")
+	if s != "" && !strings.ContainsAny(s, " \t\"'\\") {
+		return s
+	}
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+func systemdUnitPath(name string, opts Options) (string, error) {
+	dir := opts.Dir
+	if dir == "" {
+		var err error
+		if dir, err = SystemdUserDir(); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(dir, name+".service"), nil
+}
+
+// WriteSystemdUnit renders the unit for s into the unit directory and
+// returns its path.
+func WriteSystemdUnit(s Spec, opts Options) (string, error) {
+	path, err := systemdUnitPath(s.Name, opts)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, []byte(RenderSystemdUnit(s)), 0644)
+}
+
+func systemdInstall(s Spec, opts Options) (string, error) {
+	path, err := WriteSystemdUnit(s, opts)
+	if err != nil || opts.DryRun {
+		return path, err
+	}
+	if _, err := run("systemctl", "--user", "daemon-reload"); err != nil {
+		return path, err
+	}
+	_, err = run("systemctl", "--user", "enable", "--now", s.Name+".service")
+	return path, err
+}
+
+func systemdUninstall(name string, opts Options) error {
+	path, err := systemdUnitPath(name, opts)
+	if err != nil {
+		return err
+	}
+	if !opts.DryRun {
+		// Fails if the unit was never loaded - removing the file is what counts
+		run("systemctl", "--user", "disable", "--now", name+".service")
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if opts.DryRun {
+		return nil
+	}
+	_, err = run("systemctl", "--user", "daemon-reload")
+	return err
+}
+
+func systemdQuery(name string, opts Options) (Status, error) {
+	path, err := systemdUnitPath(name, opts)
+	if err != nil {
+		return Status{}, err
+	}
+	st := Status{Path: path}
+	if _, err := os.Stat(path); err == nil {
+		st.Installed = true
+	}
+	if opts.DryRun {
+		return st, nil
+	}
+	// Both commands exit non-zero for "inactive"/"disabled", the text is what matters
+	active, _ := run("systemctl", "--user", "is-active", name+".service")
+	enabled, _ := run("systemctl", "--user", "is-enabled", name+".service")
+	st.Running = active == "active"
+	st.Enabled = enabled == "enabled"
+	st.Detail = fmt.Sprintf("%s, %s", active, enabled)
+	return st, nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteSystemdUnit(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "systemd", "user")
+	spec := Spec{
+		Name:        "ltth",
+		Description: "LTTH - PupCid's Little TikTok Helper",
+		Exe:         "/opt/LTTH Launcher/launcher",
+		Args:        []string{"--headless", "--port", "3005"},
+		WorkDir:     "/opt/LTTH Launcher",
+		Env:         map[string]string{"PATH": "/usr/local/bin:/usr/bin", "HOME": "/home/100%"},
+		StopTimeout: 12500 * time.Millisecond,
+	}
+
+	path, err := WriteSystemdUnit(spec, Options{Dir: dir, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "ltth.service"); path != want {
+		t.Errorf("path = %s, want %s", path, want)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unit := string(data)
+
+	for _, line := range []string{
+		`ExecStart="/opt/LTTH Launcher/launcher" --headless --port 3005`,
+		`WorkingDirectory="/opt/LTTH Launcher"`,
+		"Restart=on-failure",
+		"KillMode=mixed",
+		"TimeoutStopSec=13",
+		"Environment=HOME=/home/100%%",
+		"Environment=PATH=/usr/local/bin:/usr/bin",
+		"WantedBy=default.target",
+	} {
+		if !strings.Contains(unit, "\n"+line+"\n") {
+			t.Errorf("unit lacks %q:\n%s", line, unit)
+		}
+	}
+	if strings.Index(unit, "[Service]") > strings.Index(unit, "ExecStart=") {
+		t.Errorf("ExecStart outside [Service]:\n%s", unit)
+	}
+
+	// Reinstalling replaces the unit
+	spec.Args = []string{"--headless"}
+	if _, err := WriteSystemdUnit(spec, Options{Dir: dir, DryRun: true}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	if !strings.Contains(string(data), "\nExecStart=\"/opt/LTTH Launcher/launcher\" --headless\n") {
+		t.Errorf("rewritten unit:\n%s", data)
+	}
+}
+
+func TestSystemdQuote(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"/usr/bin/launcher", "/usr/bin/launcher"},
+		{"--headless", "--headless"},
+		{"", `""`},
+		{"/home/a b", `"/home/a b"`},
+		{"50%", "50%%"},
+		{"$HOME", "$$HOME"},
+		{`say "hi"`, `"say \"hi\""`},
+		{`C:\x`, `"C:\\x"`},
+	}
+	for _, tt := range tests {
+		if got := systemdQuote(tt.in); got != tt.want {
+			t.Errorf("systemdQuote(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Package sse streams the launcher's progress to the splash pages connected
+// to /events (Server-Sent Events). The startup goroutine, the watchdog,
+// signal handling and the control API all report progress concurrently.
+package sse
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// clientBuffer is how many messages a slow page may fall behind before
+// further messages are dropped for it.
+const clientBuffer = 10
+
+// Hub holds the current progress and the connected pages.
+type Hub struct {
+	mu       sync.Mutex
+	clients  map[chan string]bool
+	progress int
+	status   string
+}
+
+// NewHub returns a hub at 0% showing status.
+func NewHub(status string) *Hub {
+	return &Hub{clients: make(map[chan string]bool), status: status}
+}
+
+// ProgressMessage is the payload of a progress update. Marshalled, status
+// texts may contain Windows paths and quotes.
+func ProgressMessage(value int, status string) string {
+	data, _ := json.Marshal(map[string]interface{}{"progress": value, "status": status})
+	return string(data)
+}
+
+// SetProgress records the progress and sends it to every page.
+func (h *Hub) SetProgress(value int, status string) {
+	h.mu.Lock()
+	h.progress, h.status = value, status
+	clients := h.snapshot()
+	h.mu.Unlock()
+	send(clients, ProgressMessage(value, status))
+}
+
+// SetStatus replaces the status text and keeps the progress value.
+func (h *Hub) SetStatus(status string) {
+	h.mu.Lock()
+	value := h.progress
+	h.status = status
+	clients := h.snapshot()
+	h.mu.Unlock()
+	send(clients, ProgressMessage(value, status))
+}
+
+// Progress returns the last recorded progress.
+func (h *Hub) Progress() (int, string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.progress, h.status
+}
+
+// Send delivers a raw JSON message to every page. Pages that fell behind
+// miss it instead of blocking the sender.
+func (h *Hub) Send(msg string) {
+	h.mu.Lock()
+	clients := h.snapshot()
+	h.mu.Unlock()
+	send(clients, msg)
+}
+
+// ServeHTTP streams the current progress and every later message until
+// the page disconnects.
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	client := make(chan string, clientBuffer)
+	h.mu.Lock()
+	h.clients[client] = true
+	initial := ProgressMessage(h.progress, h.status)
+	h.mu.Unlock()
+	defer func() {
+		h.mu.Lock()
+		delete(h.clients, client)
+		h.mu.Unlock()
+	}()
+
+	flusher, _ := w.(http.Flusher)
+	write := func(msg string) {
+		fmt.Fprintf(w, "data: %s\n\n", msg)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	write(initial)
+	for {
+		select {
+		case msg := <-client:
+			write(msg)
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// snapshot copies the client list; the caller holds h.mu.
+func (h *Hub) snapshot() []chan string {
+	clients := make([]chan string, 0, len(h.clients))
+	for c := range h.clients {
+		clients = append(clients, c)
+	}
+	return clients
+}
+
+func send(clients []chan string, msg string) {
+	for _, c := range clients {
+		select {
+		case c <- msg:
+		default:
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package sse
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestProgressMessage(t *testing.T) {
+	got := ProgressMessage(42, `Kopiere "C:\LTTH\app"`)
+	want := `{"progress":42,"status":"Kopiere \"C:\\LTTH\\app\""}`
+	if got != want {
+		t.Errorf("ProgressMessage() = %s, want %s", got, want)
+	}
+}
+
+func TestSetStatusKeepsProgress(t *testing.T) {
+	h := NewHub("Initialisiere...")
+	if v, s := h.Progress(); v != 0 || s != "Initialisiere..." {
+		t.Errorf("initial Progress() = %d, %q", v, s)
+	}
+	h.SetProgress(30, "Installiere Abhängigkeiten...")
+	h.SetStatus("Warte auf Server...")
+	if v, s := h.Progress(); v != 30 || s != "Warte auf Server..." {
+		t.Errorf("Progress() = %d, %q", v, s)
+	}
+}
+
+// readEvent returns the payload of the next "data:" line.
+func readEvent(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if msg, ok := strings.CutPrefix(strings.TrimSpace(line), "data: "); ok {
+			return msg
+		}
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	h := NewHub("Initialisiere...")
+	h.SetProgress(10, "Prüfe Node.js...")
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	r := bufio.NewReader(resp.Body)
+	if got, want := readEvent(t, r), ProgressMessage(10, "Prüfe Node.js..."); got != want {
+		t.Errorf("initial message = %s, want %s", got, want)
+	}
+
+	// The client is registered before the initial message is written
+	h.SetProgress(20, "Starte Server...")
+	if got, want := readEvent(t, r), ProgressMessage(20, "Starte Server..."); got != want {
+		t.Errorf("update = %s, want %s", got, want)
+	}
+	h.Send(`{"type":"log","message":"ok"}`)
+	if got := readEvent(t, r); got != `{"type":"log","message":"ok"}` {
+		t.Errorf("raw message = %s", got)
+	}
+}
+
+// TestConcurrentClients connects and drops pages while several goroutines
+// report progress; run with -race.
+func TestConcurrentClients(t *testing.T) {
+	h := NewHub("Initialisiere...")
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	stop := make(chan struct{})
+	var senders sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		senders.Add(1)
+		go func() {
+			defer senders.Done()
+			for n := 0; ; n++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				switch n % 3 {
+				case 0:
+					h.SetProgress(n%100, "Fortschritt")
+				case 1:
+					h.SetStatus("Status")
+				default:
+					h.Send(`{"type":"log"}`)
+				}
+				h.Progress()
+			}
+		}()
+	}
+
+	var clients sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		clients.Add(1)
+		go func() {
+			defer clients.Done()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer resp.Body.Close()
+			// Drop the page after the initial message
+			bufio.NewReader(resp.Body).ReadString('\n')
+		}()
+	}
+	clients.Wait()
+	close(stop)
+	senders.Wait()
+
+	// Every handler removes its client once the page is gone
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		h.mu.Lock()
+		n := len(h.clients)
+		h.mu.Unlock()
+		if n == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d clients left after disconnect", n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,221 @@
+// Package startprofile times the phases of a launcher start and keeps the
+// profiles of recent starts, so a slow start can be traced to a phase
+// instead of guessed.
+package startprofile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// HistoryFile in the config directory holds the profiles of the last Keep
+// starts, oldest first.
+const HistoryFile = ".launcher_startup_history.json"
+
+// Keep is the number of starts kept in the history.
+const Keep = 20
+
+// Phases of a start, in the order runLauncher passes them.
+const (
+	Splash    = "splash"
+	Node      = "node"
+	Preflight = "preflight"
+	Deps      = "deps"
+	Install   = "install"
+	Native    = "native"
+	AutoFix   = "autofix"
+	Profile   = "profile"
+	Spawn     = "spawn"
+	Health    = "health"
+	Plugins   = "plugins"
+)
+
+var labels = map[string]string{
+	Splash:    "Launcher & Startseite",
+	Node:      "Node.js-Prüfung",
+	Preflight: "App-Verzeichnis & Speicherplatz",
+	Deps:      "Abhängigkeiten prüfen",
+	Install:   "npm install",
+	Native:    "Native Module",
+	AutoFix:   "Auto-Fix & Konfiguration",
+	Profile:   "Profilauswahl",
+	Spawn:     "Server-Prozess starten",
+	Health:    "Erste Antwort des Servers",
+	Plugins:   "Plugins bereit",
+}
+
+// Label is the German name of a phase for the splash page and doctor.
+func Label(name string) string {
+	if label, ok := labels[name]; ok {
+		return label
+	}
+	return name
+}
+
+// Phase is the time spent in one phase. Pauses only make the splash
+// page readable and are counted apart from the work.
+type Phase struct {
+	Name    string `json:"name"`
+	Ms      int64  `json:"ms"`
+	PauseMs int64  `json:"pauseMs,omitempty"`
+}
+
+// Run is the profile of one start.
+type Run struct {
+	Started time.Time `json:"started"`
+	Fast    bool      `json:"fast"` // started with --fast, without pauses
+	Phases  []Phase   `json:"phases"`
+	TotalMs int64     `json:"totalMs"` // wall time including pauses
+}
+
+// PauseMs adds up the pauses of all phases.
+func (r Run) PauseMs() int64 {
+	var ms int64
+	for _, p := range r.Phases {
+		ms += p.PauseMs
+	}
+	return ms
+}
+
+// Recorder measures a start in progress. It is safe for concurrent use,
+// the splash server reads it while runLauncher records.
+type Recorder struct {
+	fast bool // run.Fast, read by Pause without the lock
+
+	mu     sync.Mutex
+	run    Run
+	mark   time.Time     // end of the previous phase
+	paused time.Duration // pauses since mark
+	done   bool
+}
+
+// New starts measuring. With fast, Pause returns immediately.
+func New(fast bool) *Recorder {
+	now := time.Now()
+	return &Recorder{fast: fast, run: Run{Started: now, Fast: fast}, mark: now}
+}
+
+// Mark ends a phase: the time since the previous Mark, minus pauses, is
+// counted for name. Marking a name again adds to that phase, so a phase
+// interrupted by another one keeps its place.
+func (r *Recorder) Mark(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	work := now.Sub(r.mark) - r.paused
+	if work < 0 {
+		work = 0
+	}
+	phase := Phase{Name: name, Ms: work.Milliseconds(), PauseMs: r.paused.Milliseconds()}
+	r.mark, r.paused = now, 0
+
+	for i := range r.run.Phases {
+		if r.run.Phases[i].Name == name {
+			r.run.Phases[i].Ms += phase.Ms
+			r.run.Phases[i].PauseMs += phase.PauseMs
+			return
+		}
+	}
+	r.run.Phases = append(r.run.Phases, phase)
+}
+
+// Pause waits d so a status message stays readable, unless the start is
+// fast.
+func (r *Recorder) Pause(d time.Duration) {
+	if r.fast {
+		return
+	}
+	start := time.Now()
+	time.Sleep(d)
+	r.mu.Lock()
+	r.paused += time.Since(start)
+	r.mu.Unlock()
+}
+
+// Snapshot returns the run measured so far, or the finished run.
+func (r *Recorder) Snapshot() Run {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run := r.run
+	run.Phases = append([]Phase(nil), r.run.Phases...)
+	if !r.done {
+		run.TotalMs = time.Since(run.Started).Milliseconds()
+	}
+	return run
+}
+
+// Finish stops the clock at the end of the last phase and returns the run.
+func (r *Recorder) Finish() Run {
+	r.mu.Lock()
+	if !r.done {
+		r.run.TotalMs = r.mark.Sub(r.run.Started).Milliseconds()
+		r.done = true
+	}
+	r.mu.Unlock()
+	return r.Snapshot()
+}
+
+// Load returns the recorded starts in configDir, oldest first. A missing
+// history is empty.
+func Load(configDir string) ([]Run, error) {
+	data, err := os.ReadFile(filepath.Join(configDir, HistoryFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var runs []Run
+	if err := json.Unmarshal(data, &runs); err != nil {
+		return nil, fmt.Errorf("%s: %v", HistoryFile, err)
+	}
+	return runs, nil
+}
+
+// Record adds run to the history in configDir and drops all but the last
+// Keep starts. An unreadable history is replaced.
+func Record(configDir string, run Run) error {
+	runs, _ := Load(configDir)
+	runs = append(runs, run)
+	if len(runs) > Keep {
+		runs = runs[len(runs)-Keep:]
+	}
+	data, err := json.MarshalIndent(runs, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(configDir, HistoryFile+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(configDir, HistoryFile))
+}
+
+// Seconds formats milliseconds for display: "0.4 s", "12.3 s", "2:05 min".
+func Seconds(ms int64) string {
+	if ms >= 60*1000 {
+		s := (ms + 500) / 1000
+		return fmt.Sprintf("%d:%02d min", s/60, s%60)
+	}
+	return fmt.Sprintf("%.1f s", float64(ms)/1000)
+}
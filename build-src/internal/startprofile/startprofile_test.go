@@ -0,0 +1,214 @@
+package startprofile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const step = 20 * time.Millisecond
+
+func TestMarkAccumulates(t *testing.T) {
+	r := New(false)
+	time.Sleep(step)
+	r.Mark(Splash)
+	time.Sleep(step)
+	r.Pause(step)
+	r.Mark(Node)
+	// Deps is interrupted by Install and continues afterwards
+	time.Sleep(step)
+	r.Mark(Deps)
+	time.Sleep(step)
+	r.Mark(Install)
+	time.Sleep(step)
+	r.Mark(Deps)
+	run := r.Finish()
+
+	var names []string
+	for _, p := range run.Phases {
+		names = append(names, p.Name)
+	}
+	if got := strings.Join(names, " "); got != "splash node deps install" {
+		t.Fatalf("phases = %s", got)
+	}
+	tests := []struct {
+		phase    Phase
+		minMs    int64
+		minPause int64
+	}{
+		{run.Phases[0], 20, 0},
+		{run.Phases[1], 20, 20},
+		{run.Phases[2], 40, 0},
+		{run.Phases[3], 20, 0},
+	}
+	for _, tt := range tests {
+		if tt.phase.Ms < tt.minMs || tt.phase.PauseMs < tt.minPause {
+			t.Errorf("%s = %+v, want at least %d ms work and %d ms pauses", tt.phase.Name, tt.phase, tt.minMs, tt.minPause)
+		}
+		if tt.minPause == 0 && tt.phase.PauseMs != 0 {
+			t.Errorf("%s has %d ms pauses", tt.phase.Name, tt.phase.PauseMs)
+		}
+	}
+	var work int64
+	for _, p := range run.Phases {
+		work += p.Ms
+	}
+	if run.PauseMs() != run.Phases[1].PauseMs || run.TotalMs < work+run.PauseMs() {
+		t.Errorf("total %d ms, pauses %d ms, work %d ms", run.TotalMs, run.PauseMs(), work)
+	}
+
+	// Finished: the clock stands still
+	time.Sleep(step)
+	if again := r.Snapshot(); again.TotalMs != run.TotalMs {
+		t.Errorf("TotalMs after Finish moved from %d to %d", run.TotalMs, again.TotalMs)
+	}
+}
+
+func TestPauseFast(t *testing.T) {
+	r := New(true)
+	start := time.Now()
+	r.Pause(time.Hour)
+	if time.Since(start) > time.Second {
+		t.Fatal("Pause waited in a fast start")
+	}
+	r.Mark(Splash)
+	if run := r.Finish(); !run.Fast || run.PauseMs() != 0 {
+		t.Errorf("Finish() = %+v", run)
+	}
+}
+
+// TestRecorderConcurrent reads the recorder like the splash server does
+// while the start records; run with -race.
+func TestRecorderConcurrent(t *testing.T) {
+	for _, fast := range []bool{true, false} {
+		r := New(fast)
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for _, name := range []string{Splash, Node, Preflight, Deps} {
+				r.Pause(time.Millisecond)
+				r.Mark(name)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				r.Snapshot()
+			}
+		}()
+		wg.Wait()
+		if n := len(r.Finish().Phases); n != 4 {
+			t.Errorf("fast=%v: %d phases", fast, n)
+		}
+	}
+}
+
+func TestRecordKeepsLast(t *testing.T) {
+	tests := []struct {
+		before, first, total int // existing runs, index of the oldest kept run, runs afterwards
+	}{
+		{0, 0, 1},
+		{Keep - 1, 0, Keep},
+		{Keep, 1, Keep},
+		{Keep + 5, 6, Keep},
+	}
+	base := time.Date(2025, 1, 2, 20, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		dir := filepath.Join(t.TempDir(), "config")
+		if tt.before > 0 {
+			writeHistory(t, dir, base, tt.before)
+		}
+		if err := Record(dir, Run{Started: base.Add(time.Duration(tt.before) * time.Minute), TotalMs: 1234}); err != nil {
+			t.Fatal(err)
+		}
+		runs, err := Load(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(runs) != tt.total {
+			t.Fatalf("%d runs before: %d kept, want %d", tt.before, len(runs), tt.total)
+		}
+		if want := base.Add(time.Duration(tt.first) * time.Minute); !runs[0].Started.Equal(want) {
+			t.Errorf("%d runs before: oldest kept %v, want %v", tt.before, runs[0].Started, want)
+		}
+		if last := runs[len(runs)-1]; last.TotalMs != 1234 {
+			t.Errorf("%d runs before: newest run %+v", tt.before, last)
+		}
+	}
+}
+
+// writeHistory stores n runs one minute apart, possibly more than Record
+// would keep.
+func writeHistory(t *testing.T, dir string, base time.Time, n int) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	b.WriteString("[")
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(`{"started":"` + base.Add(time.Duration(i)*time.Minute).Format(time.RFC3339) + `","phases":[]}`)
+	}
+	b.WriteString("]")
+	if err := os.WriteFile(filepath.Join(dir, HistoryFile), []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if runs, err := Load(dir); runs != nil || err != nil {
+		t.Errorf("Load() without history = %v, %v", runs, err)
+	}
+	os.WriteFile(filepath.Join(dir, HistoryFile), []byte("{broken"), 0644)
+	if _, err := Load(dir); err == nil {
+		t.Error("Load() accepted a broken history")
+	}
+	// Record replaces it
+	if err := Record(dir, Run{TotalMs: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if runs, err := Load(dir); err != nil || len(runs) != 1 {
+		t.Errorf("Load() = %v, %v", runs, err)
+	}
+}
+
+func TestSeconds(t *testing.T) {
+	tests := []struct {
+		ms   int64
+		want string
+	}{
+		{0, "0.0 s"},
+		{49, "0.0 s"},
+		{420, "0.4 s"},
+		{1000, "1.0 s"},
+		{12345, "12.3 s"},
+		{59949, "59.9 s"},
+		{60000, "1:00 min"},
+		{60499, "1:00 min"},
+		{60500, "1:01 min"},
+		{125000, "2:05 min"},
+		{3600000, "60:00 min"},
+	}
+	for _, tt := range tests {
+		if got := Seconds(tt.ms); got != tt.want {
+			t.Errorf("Seconds(%d) = %q, want %q", tt.ms, got, tt.want)
+		}
+	}
+}
+
+func TestLabel(t *testing.T) {
+	if got := Label(Install); got != "npm install" {
+		t.Errorf("Label(install) = %q", got)
+	}
+	if got := Label("unknown"); got != "unknown" {
+		t.Errorf("Label(unknown) = %q", got)
+	}
+}
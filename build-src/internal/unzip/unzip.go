@@ -0,0 +1,138 @@
+// Package unzip extracts downloaded archives (repository snapshots,
+// third-party plugins) without trusting their entry names: nothing is
+// written outside the target directory, links are refused and the total
+// size is capped.
+package unzip
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// MaxSize caps the uncompressed size of one archive.
+const MaxSize = 4 << 30
+
+// ErrTooLarge is returned for archives that unpack to more than MaxSize.
+var ErrTooLarge = errors.New("Archiv ist entpackt größer als 4 GB")
+
+// Extract unpacks zipPath into destDir. With stripRoot the first path
+// component of every entry is dropped, for archives that wrap everything in
+// one top-level folder (GitHub's "<repo>-<branch>/"). All entry names are
+// checked before the first file is written.
+func Extract(zipPath, destDir string, stripRoot bool) error {
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	targets := make([]string, len(r.File))
+	var total uint64
+	for i, f := range r.File {
+		name := f.Name
+		if stripRoot {
+			name = stripFirst(name)
+			if name == "" {
+				continue
+			}
+		}
+		target, err := entryTarget(name, destDir)
+		if err != nil {
+			return err
+		}
+		if f.Mode()&os.ModeType&^os.ModeDir != 0 {
+			return fmt.Errorf("%s: Links und Sonderdateien werden nicht entpackt", f.Name)
+		}
+		total += f.UncompressedSize64
+		if total > MaxSize {
+			return ErrTooLarge
+		}
+		targets[i] = target
+	}
+
+	var written int64
+	for i, f := range r.File {
+		target := targets[i]
+		if target == "" {
+			continue
+		}
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+		n, err := extractFile(f, target, MaxSize-written)
+		if err != nil {
+			return err
+		}
+		written += n
+	}
+	return nil
+}
+
+// extractFile writes one entry, reading at most limit bytes no matter what
+// its header claims.
+func extractFile(f *zip.File, target string, limit int64) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return 0, err
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+
+	// Keep the executable bit for scripts, never setuid or world-writable
+	mode := f.Mode().Perm()&0755 | 0644
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(out, io.LimitReader(rc, limit+1))
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && n > limit {
+		err = ErrTooLarge
+	}
+	return n, err
+}
+
+// entryTarget maps an entry name to a path below destDir and rejects
+// absolute names, drive letters and ".." components (zip slip).
+func entryTarget(name, destDir string) (string, error) {
+	slashed := strings.ReplaceAll(name, "\\", "/")
+	if strings.HasPrefix(slashed, "/") || filepath.VolumeName(name) != "" || strings.Contains(slashed, ":") {
+		return "", fmt.Errorf("unsicherer Pfad im Archiv: %s", name)
+	}
+	for _, part := range strings.Split(slashed, "/") {
+		if part == ".." {
+			return "", fmt.Errorf("unsicherer Pfad im Archiv: %s", name)
+		}
+	}
+	cleaned := path.Clean(slashed)
+	if cleaned == "." {
+		return "", nil
+	}
+	dest := filepath.Clean(destDir)
+	target := filepath.Join(dest, filepath.FromSlash(cleaned))
+	if !strings.HasPrefix(target, dest+string(filepath.Separator)) {
+		return "", fmt.Errorf("unsicherer Pfad im Archiv: %s", name)
+	}
+	return target, nil
+}
+
+// stripFirst drops everything up to and including the first separator.
+func stripFirst(name string) string {
+	if i := strings.IndexAny(name, "/\\"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,130 @@
+package unzip
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type entry struct {
+	name string
+	mode os.FileMode
+	body string
+}
+
+func writeZip(t *testing.T, entries []entry) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "test.zip")
+	f, err := os.Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for _, e := range entries {
+		hdr := &zip.FileHeader{Name: e.name, Method: zip.Deflate}
+		if e.mode != 0 {
+			hdr.SetMode(e.mode)
+		}
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(e.body))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	return p
+}
+
+func TestExtractRejectsUnsafeEntries(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry entry
+	}{
+		{"parent", entry{name: "../evil.txt"}},
+		{"nested parent", entry{name: "plugin/../../evil.txt"}},
+		{"backslash parent", entry{name: `plugin\..\..\evil.txt`}},
+		{"absolute", entry{name: "/tmp/evil.txt"}},
+		{"backslash absolute", entry{name: `\evil.txt`}},
+		{"drive letter", entry{name: "C:/evil.txt"}},
+		{"symlink", entry{name: "plugin/link", mode: os.ModeSymlink | 0777, body: "/etc/passwd"}},
+		{"device", entry{name: "plugin/dev", mode: os.ModeDevice | 0644}},
+	}
+	for _, tt := range tests {
+		base := t.TempDir()
+		dest := filepath.Join(base, "dest")
+		// A harmless entry first: nothing may be written before the check
+		zipPath := writeZip(t, []entry{{name: "plugin/ok.txt", body: "ok"}, tt.entry})
+		if err := Extract(zipPath, dest, false); err == nil {
+			t.Errorf("%s: Extract accepted %q", tt.name, tt.entry.name)
+		}
+		if _, err := os.Stat(dest); !os.IsNotExist(err) {
+			t.Errorf("%s: files were written before the archive was rejected", tt.name)
+		}
+		if _, err := os.Stat(filepath.Join(base, "evil.txt")); !os.IsNotExist(err) {
+			t.Errorf("%s: entry escaped the target directory", tt.name)
+		}
+	}
+}
+
+func TestExtract(t *testing.T) {
+	zipPath := writeZip(t, []entry{
+		{name: "repo-main/"},
+		{name: "repo-main/README.md", body: "readme"},
+		{name: "repo-main/bin/run.sh", mode: os.ModeSetuid | 0777, body: "#!/bin/sh"},
+		{name: "repo-main/./docs/a.md", body: "a"},
+	})
+
+	dest := t.TempDir()
+	if err := Extract(zipPath, dest, true); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{"README.md": "readme", "bin/run.sh": "#!/bin/sh", "docs/a.md": "a"} {
+		data, err := os.ReadFile(filepath.Join(dest, filepath.FromSlash(name)))
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v, want %q", name, data, err, want)
+		}
+	}
+	info, err := os.Stat(filepath.Join(dest, "bin", "run.sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode(); mode&(os.ModeSetuid|0002) != 0 {
+		t.Errorf("run.sh mode %v keeps setuid or world-writable", mode)
+	}
+
+	dest = t.TempDir()
+	if err := Extract(zipPath, dest, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "repo-main", "README.md")); err != nil {
+		t.Errorf("without stripRoot: %v", err)
+	}
+}
+
+func TestEntryTarget(t *testing.T) {
+	dest := filepath.Join("base", "dest")
+	tests := []struct {
+		name string
+		want string // "" with ok: no target (the root itself)
+		ok   bool
+	}{
+		{"a/b.txt", filepath.Join(dest, "a", "b.txt"), true},
+		{`a\b.txt`, filepath.Join(dest, "a", "b.txt"), true},
+		{"./", "", true},
+		{"a/..b", filepath.Join(dest, "a", "..b"), true},
+		{"a/../b", "", false},
+		{"..", "", false},
+		{"/a", "", false},
+		{"a:b", "", false},
+	}
+	for _, tt := range tests {
+		got, err := entryTarget(tt.name, dest)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("entryTarget(%q) = %q, %v, want %q, ok=%v", tt.name, got, err, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Package watchdog decides when a running but unresponsive server has to be
+// restarted and collects what is needed to find out why it hung.
+package watchdog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Tracker counts consecutive bad health probes. A probe is bad if it failed
+// or took longer than Latency; Failures bad probes in a row trip it.
+type Tracker struct {
+	Failures int
+	Latency  time.Duration
+
+	strikes int
+}
+
+// Observe records one probe result and reports whether the server should be
+// restarted now. The counter starts over after tripping.
+func (t *Tracker) Observe(latency time.Duration, err error) bool {
+	if err == nil && latency <= t.Latency {
+		t.strikes = 0
+		return false
+	}
+	t.strikes++
+	if t.strikes < t.Failures {
+		return false
+	}
+	t.strikes = 0
+	return true
+}
+
+// Strikes returns the number of bad probes in a row so far.
+func (t *Tracker) Strikes() int { return t.strikes }
+
+// Reset forgets earlier bad probes, e.g. after the server was restarted.
+func (t *Tracker) Reset() { t.strikes = 0 }
+
+// RestartLimit bounds automatic restarts, so a server that crashes or hangs
+// right after every start is not restarted forever.
+type RestartLimit struct {
+	Max    int // restarts allowed within Window
+	Window time.Duration
+
+	times []time.Time
+}
+
+// Allow reports whether another restart at now stays within the limit and
+// counts it if so.
+func (r *RestartLimit) Allow(now time.Time) bool {
+	kept := r.times[:0]
+	for _, t := range r.times {
+		if now.Sub(t) < r.Window {
+			kept = append(kept, t)
+		}
+	}
+	r.times = kept
+	if len(r.times) >= r.Max {
+		return false
+	}
+	r.times = append(r.times, now)
+	return true
+}
+
+// Snapshot is written before a hung server is restarted.
+type Snapshot struct {
+	Time             time.Time       `json:"time"`
+	Reason           string          `json:"reason"`
+	PID              int             `json:"pid"`
+	LastError        string          `json:"lastError,omitempty"`
+	LastLatency      string          `json:"lastLatency"`
+	ConnectionHealth json.RawMessage `json:"connectionHealth,omitempty"`
+	HealthError      string          `json:"connectionHealthError,omitempty"`
+	LogTail          []string        `json:"logTail"`
+}
+
+// Write stores the snapshot as dir/watchdog_<timestamp>.json and returns
+// the file path.
+func (s *Snapshot) Write(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("watchdog_%s.json", s.Time.Format("2006-01-02_15-04-05")))
+	return path, os.WriteFile(path, data, 0644)
+}
+
+// tailBytes bounds how much of a (possibly huge) log file TailLines reads.
+const tailBytes = 64 * 1024
+
+// TailLines returns the last n lines of the file at path.
+func TailLines(path string, n int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := info.Size() - tailBytes
+	if offset < 0 {
+		offset = 0
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\r\n"), "\n")
+	if offset > 0 && len(lines) > 0 {
+		lines = lines[1:] // Most likely cut in the middle
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], "\r")
+	}
+	return lines, nil
+}
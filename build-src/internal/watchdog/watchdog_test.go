@@ -0,0 +1,109 @@
+package watchdog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrackerObserve(t *testing.T) {
+	const (
+		ok   = "ok"   // fast answer
+		slow = "slow" // answered above the latency threshold
+		fail = "fail" // no answer
+	)
+	tests := []struct {
+		name     string
+		failures int
+		probes   string // space separated
+		trips    string // result of every probe, t or .
+	}{
+		{"healthy", 3, "ok ok ok ok", ". . . ."},
+		{"threshold reached", 3, "fail fail fail", ". . t"},
+		{"slow answers count", 3, "slow fail slow", ". . t"},
+		{"success resets", 3, "fail fail ok fail fail fail", ". . . . . t"},
+		{"starts over after tripping", 2, "fail fail fail fail", ". t . t"},
+		{"single failure trips", 1, "ok fail ok", ". t ."},
+	}
+	for _, tt := range tests {
+		tr := &Tracker{Failures: tt.failures, Latency: time.Second}
+		var got []string
+		for _, p := range strings.Fields(tt.probes) {
+			latency, err := 10*time.Millisecond, error(nil)
+			switch p {
+			case slow:
+				latency = 2 * time.Second
+			case fail:
+				err = errors.New("connection refused")
+			}
+			if tr.Observe(latency, err) {
+				got = append(got, "t")
+			} else {
+				got = append(got, ".")
+			}
+		}
+		if g := strings.Join(got, " "); g != tt.trips {
+			t.Errorf("%s: trips = %s, want %s", tt.name, g, tt.trips)
+		}
+	}
+}
+
+func TestTrackerReset(t *testing.T) {
+	tr := &Tracker{Failures: 3, Latency: time.Second}
+	tr.Observe(0, errors.New("timeout"))
+	tr.Observe(0, errors.New("timeout"))
+	if tr.Strikes() != 2 {
+		t.Errorf("Strikes() = %d, want 2", tr.Strikes())
+	}
+	tr.Reset()
+	if tr.Strikes() != 0 || tr.Observe(0, errors.New("timeout")) {
+		t.Errorf("Reset() kept earlier strikes")
+	}
+}
+
+func TestRestartLimit(t *testing.T) {
+	start := time.Date(2025, 1, 2, 20, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		max     int
+		minutes []int // restart attempts, minutes after start
+		allowed string
+	}{
+		{"within limit", 3, []int{0, 1, 2}, "y y y"},
+		{"limit reached", 3, []int{0, 1, 2, 3}, "y y y n"},
+		{"old restarts expire", 2, []int{0, 10, 61, 65}, "y y y n"},
+		{"denied attempts do not count", 1, []int{0, 30, 59, 60}, "y n n y"},
+		{"no automatic restarts", 0, []int{0, 120}, "n n"},
+	}
+	for _, tt := range tests {
+		r := &RestartLimit{Max: tt.max, Window: time.Hour}
+		var got []string
+		for _, m := range tt.minutes {
+			if r.Allow(start.Add(time.Duration(m) * time.Minute)) {
+				got = append(got, "y")
+			} else {
+				got = append(got, "n")
+			}
+		}
+		if g := strings.Join(got, " "); g != tt.allowed {
+			t.Errorf("%s: Allow() = %s, want %s", tt.name, g, tt.allowed)
+		}
+	}
+}
+
+func TestTailLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "launcher.log")
+	if err := os.WriteFile(path, []byte("one\r\ntwo\r\nthree\r\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	lines, err := TailLines(path, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(lines, "|") != "two|three" {
+		t.Errorf("TailLines() = %q", lines)
+	}
+}
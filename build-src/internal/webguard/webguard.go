@@ -0,0 +1,166 @@
+// Package webguard protects the launcher's local HTTP server from other
+// websites open in the same browser. Every launch gets a random token that
+// is handed to the browser in the opened URL and kept in a cookie; state
+// changing endpoints additionally require POST and a same-origin request.
+package webguard
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// HeaderName lets scripts (e.g. Stream Deck actions) authenticate without a
+// cookie. Browsers cannot send custom headers cross-site without a CORS
+// preflight, which the launcher never answers.
+const HeaderName = "X-LTTH-Token"
+
+// Guard holds the per-launch token and the host names the server answers to.
+type Guard struct {
+	token  string
+	cookie string
+	hosts  map[string]bool
+}
+
+// New creates a guard for a server listening on addr (host:port).
+func New(addr string) (*Guard, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	return &Guard{
+		token: hex.EncodeToString(buf),
+		// Cookies are not port specific, so the port keeps launchers apart
+		cookie: "ltth_launcher_" + port,
+		hosts: map[string]bool{
+			"127.0.0.1:" + port: true,
+			"localhost:" + port: true,
+			"[::1]:" + port:     true,
+		},
+	}, nil
+}
+
+// Token returns the per-launch secret.
+func (g *Guard) Token() string {
+	return g.token
+}
+
+// URL appends the token to base so the opened page can set its cookie.
+func (g *Guard) URL(base string) string {
+	return strings.TrimSuffix(base, "/") + "/?token=" + g.token
+}
+
+// Public only checks the Host header, which defeats DNS rebinding. Use it
+// for harmless resources like images.
+func (g *Guard) Public(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !g.hosts[r.Host] {
+			http.Error(w, "invalid host", http.StatusForbidden)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// Page protects pages and read-only endpoints. A valid ?token= sets the
+// cookie and redirects to the same URL without the token; afterwards the
+// cookie is required. Non-GET requests are checked like Action, so a page
+// can post a form to itself.
+func (g *Guard) Page(h http.Handler) http.Handler {
+	return g.Public(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			g.checkAction(w, r, h)
+			return
+		}
+
+		if t := r.URL.Query().Get("token"); t != "" {
+			if !g.valid(t) {
+				http.Error(w, "invalid token", http.StatusForbidden)
+				return
+			}
+			http.SetCookie(w, &http.Cookie{
+				Name:     g.cookie,
+				Value:    g.token,
+				Path:     "/",
+				HttpOnly: true,
+				SameSite: http.SameSiteStrictMode,
+			})
+			clean := *r.URL
+			q := clean.Query()
+			q.Del("token")
+			clean.RawQuery = q.Encode()
+			http.Redirect(w, r, clean.RequestURI(), http.StatusSeeOther)
+			return
+		}
+
+		if !g.hasCookie(r) && !g.valid(r.Header.Get(HeaderName)) {
+			http.Error(w, "Zugriff verweigert - bitte den Launcher-Link verwenden, den der Launcher geöffnet hat.", http.StatusForbidden)
+			return
+		}
+		h.ServeHTTP(w, r)
+	}))
+}
+
+// Action protects control endpoints: POST only, and either the token header
+// or the cookie on a request the browser marks as same-origin.
+func (g *Guard) Action(h http.Handler) http.Handler {
+	return g.Public(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.checkAction(w, r, h)
+	}))
+}
+
+func (g *Guard) checkAction(w http.ResponseWriter, r *http.Request, h http.Handler) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if origin := r.Header.Get("Origin"); origin != "" && !g.sameOrigin(origin) {
+		http.Error(w, "cross-site request rejected", http.StatusForbidden)
+		return
+	}
+	if g.valid(r.Header.Get(HeaderName)) {
+		h.ServeHTTP(w, r)
+		return
+	}
+	if !g.hasCookie(r) || !g.browserSameOrigin(r) {
+		http.Error(w, "cross-site request rejected", http.StatusForbidden)
+		return
+	}
+	h.ServeHTTP(w, r)
+}
+
+// browserSameOrigin requires positive proof from the browser that the
+// request came from one of our own pages.
+func (g *Guard) browserSameOrigin(r *http.Request) bool {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		return g.sameOrigin(origin)
+	}
+	site := r.Header.Get("Sec-Fetch-Site")
+	return site == "same-origin"
+}
+
+func (g *Guard) sameOrigin(origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || u.Scheme != "http" {
+		return false
+	}
+	return g.hosts[u.Host]
+}
+
+func (g *Guard) hasCookie(r *http.Request) bool {
+	c, err := r.Cookie(g.cookie)
+	return err == nil && g.valid(c.Value)
+}
+
+func (g *Guard) valid(token string) bool {
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(g.token)) == 1
+}
@@ -0,0 +1,151 @@
+package webguard
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testAddr = "127.0.0.1:58734"
+
+func newGuard(t *testing.T) (*Guard, http.Handler) {
+	t.Helper()
+	g, err := New(testAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return g, ok
+}
+
+func request(method, target string, header map[string]string, cookie *http.Cookie) *http.Request {
+	r := httptest.NewRequest(method, target, nil)
+	r.Host = testAddr
+	for k, v := range header {
+		r.Header.Set(k, v)
+	}
+	if cookie != nil {
+		r.AddCookie(cookie)
+	}
+	return r
+}
+
+func TestPage(t *testing.T) {
+	g, ok := newGuard(t)
+	page := g.Page(ok)
+	valid := &http.Cookie{Name: "ltth_launcher_58734", Value: g.Token()}
+
+	tests := []struct {
+		name   string
+		target string
+		host   string
+		header map[string]string
+		cookie *http.Cookie
+		want   int
+	}{
+		{"missing token", "/", "", nil, nil, http.StatusForbidden},
+		{"wrong token", "/?token=nope", "", nil, nil, http.StatusForbidden},
+		{"wrong cookie", "/", "", nil, &http.Cookie{Name: valid.Name, Value: "nope"}, http.StatusForbidden},
+		{"cookie of another launcher", "/", "", nil, &http.Cookie{Name: "ltth_launcher_3000", Value: g.Token()}, http.StatusForbidden},
+		{"valid cookie", "/", "", nil, valid, http.StatusOK},
+		{"valid header", "/logs", "", map[string]string{HeaderName: g.Token()}, nil, http.StatusOK},
+		{"foreign host", "/", "evil.example:58734", nil, valid, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		r := request(http.MethodGet, tt.target, tt.header, tt.cookie)
+		if tt.host != "" {
+			r.Host = tt.host
+		}
+		w := httptest.NewRecorder()
+		page.ServeHTTP(w, r)
+		if w.Code != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, w.Code, tt.want)
+		}
+	}
+}
+
+func TestPageTokenSetsCookie(t *testing.T) {
+	g, ok := newGuard(t)
+	w := httptest.NewRecorder()
+	g.Page(ok).ServeHTTP(w, request(http.MethodGet, "/settings?token="+g.Token()+"&tab=env", nil, nil))
+
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("status %d, want %d", w.Code, http.StatusSeeOther)
+	}
+	if loc := w.Header().Get("Location"); loc != "/settings?tab=env" {
+		t.Errorf("redirect to %q, want the URL without token", loc)
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("got %d cookies, want 1", len(cookies))
+	}
+	c := cookies[0]
+	if c.Name != "ltth_launcher_58734" || c.Value != g.Token() || !c.HttpOnly || c.SameSite != http.SameSiteStrictMode {
+		t.Errorf("cookie %+v", c)
+	}
+
+	w = httptest.NewRecorder()
+	g.Page(ok).ServeHTTP(w, request(http.MethodGet, "/settings?tab=env", nil, c))
+	if w.Code != http.StatusOK {
+		t.Errorf("with the set cookie: status %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestAction(t *testing.T) {
+	g, ok := newGuard(t)
+	action := g.Action(ok)
+	valid := &http.Cookie{Name: "ltth_launcher_58734", Value: g.Token()}
+	sameOrigin := map[string]string{"Origin": "http://127.0.0.1:58734"}
+
+	tests := []struct {
+		name   string
+		method string
+		header map[string]string
+		token  bool // send the X-LTTH-Token header
+		cookie *http.Cookie
+		want   int
+	}{
+		{"same-origin POST with cookie", http.MethodPost, sameOrigin, false, valid, http.StatusOK},
+		{"localhost alias", http.MethodPost, map[string]string{"Origin": "http://localhost:58734"}, false, valid, http.StatusOK},
+		{"Sec-Fetch-Site same-origin", http.MethodPost, map[string]string{"Sec-Fetch-Site": "same-origin"}, false, valid, http.StatusOK},
+		{"token header without cookie", http.MethodPost, nil, true, nil, http.StatusOK},
+		{"cross-origin POST with cookie", http.MethodPost, map[string]string{"Origin": "https://evil.example"}, false, valid, http.StatusForbidden},
+		{"cross-origin POST with token header", http.MethodPost, map[string]string{"Origin": "https://evil.example"}, true, nil, http.StatusForbidden},
+		{"other port", http.MethodPost, map[string]string{"Origin": "http://127.0.0.1:3000"}, false, valid, http.StatusForbidden},
+		{"cross-site fetch metadata", http.MethodPost, map[string]string{"Sec-Fetch-Site": "cross-site"}, false, valid, http.StatusForbidden},
+		{"no origin proof", http.MethodPost, nil, false, valid, http.StatusForbidden},
+		{"same-origin without cookie", http.MethodPost, sameOrigin, false, nil, http.StatusForbidden},
+		{"same-origin with wrong cookie", http.MethodPost, sameOrigin, false, &http.Cookie{Name: valid.Name, Value: "nope"}, http.StatusForbidden},
+		{"GET", http.MethodGet, sameOrigin, false, valid, http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		r := request(tt.method, "/api/launcher/restart", tt.header, tt.cookie)
+		if tt.token {
+			r.Header.Set(HeaderName, g.Token())
+		}
+		w := httptest.NewRecorder()
+		action.ServeHTTP(w, r)
+		if w.Code != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, w.Code, tt.want)
+		}
+	}
+}
+
+func TestPagePostIsAnAction(t *testing.T) {
+	g, ok := newGuard(t)
+	valid := &http.Cookie{Name: "ltth_launcher_58734", Value: g.Token()}
+
+	w := httptest.NewRecorder()
+	g.Page(ok).ServeHTTP(w, request(http.MethodPost, "/settings", map[string]string{"Origin": "http://evil.example"}, valid))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("cross-origin form post: status %d, want %d", w.Code, http.StatusForbidden)
+	}
+
+	w = httptest.NewRecorder()
+	g.Page(ok).ServeHTTP(w, request(http.MethodPost, "/settings", map[string]string{"Origin": "http://127.0.0.1:58734"}, valid))
+	if w.Code != http.StatusOK {
+		t.Errorf("same-origin form post: status %d, want %d", w.Code, http.StatusOK)
+	}
+}
@@ -2,6 +2,9 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"html/template"
 	"io"
@@ -10,37 +13,161 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/backup"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/changelog"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/config"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/dotenv"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/instancelock"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/logsink"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/nodeabi"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/npmcache"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/npmdeps"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/portowner"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/plugins"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/preflight"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/procutil"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/profiles"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/selfupdate"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/serverapi"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/service"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/sse"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/startprofile"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/watchdog"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/webguard"
 	"github.com/pkg/browser"
 )
 
 const (
-	// CREATE_NO_WINDOW flag for Windows to hide console window
-	createNoWindow = 0x08000000
+	// envBaseFile is a copy of the .env.example the user's .env was last
+	// merged with; it makes the merge of new keys a three-way merge
+	envBaseFile = ".env.example.base"
+
+	// defaultServerPort is the port the Node.js server listens on unless
+	// the launcher has to move it because another program holds it
+	defaultServerPort = 3000
+
+	// healthPath is requested by the startup health check and the watchdog
+	healthPath = "/dashboard.html"
+)
+
+// Set at build time, see README: -ldflags "-X main.launcherVersion=1.2.1
+// -X main.updatePublicKey=<base64 ed25519 key>". Without both the launcher
+// does not update itself.
+var (
+	launcherVersion = "dev"
+	updatePublicKey = ""
 )
 
+// Exit codes. Service managers (systemd Restart=on-failure) restart the
+// launcher on anything but exitOK.
+const (
+	exitOK             = 0
+	exitFailure        = 1 // startup failed or the server exited with an error
+	exitConfig         = 2 // invalid launcher.json or flags, same as the flag package
+	exitAlreadyRunning = 3
+)
+
+// pendingRestart is a non-critical restart waiting for the LIVE to end
+type pendingRestart struct {
+	Reasons  []string `json:"reasons"`
+	Since    string   `json:"since"`
+	Username string   `json:"username,omitempty"`
+}
+
+// watchdogStatus is reported by the control API
+type watchdogStatus struct {
+	Enabled       bool   `json:"enabled"`
+	Strikes       int    `json:"strikes"` // bad probes in a row
+	LastLatencyMs int64  `json:"lastLatencyMs"`
+	LastError     string `json:"lastError,omitempty"`
+	Restarts      int    `json:"restarts"`
+	LastSnapshot  string `json:"lastSnapshot,omitempty"`
+	GaveUp        bool   `json:"gaveUp"` // restart limit reached, the launcher exits
+}
+
+// serverProcess tracks a running Node.js server child
+type serverProcess struct {
+	cmd     *exec.Cmd
+	started time.Time
+	done    chan struct{} // closed once the process has exited
+	err     error         // result of cmd.Wait, valid after done is closed
+	code    int           // exit code, valid after done is closed
+}
+
 type Launcher struct {
+	cfg          config.Config
 	nodePath     string
+	exeDir       string
 	appDir       string
-	progress     int
-	status       string
-	clients      map[chan string]bool
+	profile      string   // Streamer profile chosen at launch, empty: the server's last active one
+	events       *sse.Hub // Progress and messages for the splash page
 	logFile      *os.File
 	logger       *log.Logger
-	envFileFixed bool // Track if we auto-created .env file
+	console      *logsink.Writer // Copy of the log on stdout in headless mode
+	envFileFixed bool   // Track if we auto-created .env file
+	port         int    // Port the Node.js server is started on
+	nodeVersion  string // Reported by the control API
+	appVersion   string // app/package.json version
+	lastVersion  string // App version of the previous successful start, for "what's new"
+	lock         *instancelock.Lock
+	guard        *webguard.Guard // Token/CSRF protection of the splash server
+
+	serverMu  sync.Mutex
+	server    *serverProcess
+	held      bool // Stopped via the control API, waiting for a start request
+	restarts  int
+	lastExit  *int // Exit code of the previous server process
+	pending   *pendingRestart
+	watchdog  watchdogStatus
+	limit     watchdog.RestartLimit
+	restartMu sync.Mutex    // Held for the whole stop+start of a restart
+	stopping  atomic.Bool   // Set once a shutdown was requested
+	ready     atomic.Bool   // Startup finished, the control API may start/stop
+	changed   chan struct{} // Signalled when a new server process was started
+	picking   atomic.Bool   // The splash page shows the profile picker
+	picked    chan string   // Profile chosen in the picker
+	pickUntil time.Time     // When the picker gives up and keeps the last profile
+
+	relocation *configRelocation // Offered on the splash page while the config dir is not writable
+	relocated  chan struct{}     // The offer was accepted or declined
+
+	timing    *startprofile.Recorder // Phase times of this start
+	lastStart *startprofile.Run      // Phase times of the previous start, for comparison
 }
 
+// configRelocation is the JSON answer of GET /api/launcher/config-relocation
+type configRelocation struct {
+	Offered          bool      `json:"offered"`
+	From             string    `json:"from,omitempty"`
+	To               string    `json:"to,omitempty"` // suggested writable directory
+	RemainingSeconds int       `json:"remainingSeconds,omitempty"`
+	until            time.Time // when the start continues without moving
+}
+
+// relocateTimeout is how long the splash page offers to move an
+// unwritable config directory before the start continues without it
+const relocateTimeout = 2 * time.Minute
+
 func NewLauncher() *Launcher {
 	return &Launcher{
-		status:       "Initialisiere...",
-		progress:     0,
-		clients:      make(map[chan string]bool),
+		events:       sse.NewHub("Initialisiere..."),
 		envFileFixed: false,
+		port:         defaultServerPort,
+		cfg:          config.Default(),
+		changed:      make(chan struct{}, 1),
+		picked:       make(chan string, 1),
+		relocated:    make(chan struct{}, 1),
+		timing:       startprofile.New(false),
 	}
 }
 
@@ -65,6 +192,9 @@ func (l *Launcher) setupLogging(appDir string) error {
 	// Only write to file (not stdout) because in GUI mode stdout doesn't exist
 	// This prevents silent failures when built with -H windowsgui
 	l.logger = log.New(logFile, "", log.LstdFlags)
+	if l.console != nil {
+		l.logger.SetOutput(io.MultiWriter(logFile, l.console))
+	}
 
 	l.logger.Println("========================================")
 	l.logger.Println("TikTok Stream Tool - Launcher Log")
@@ -91,6 +221,16 @@ func (l *Launcher) closeLogging() {
 		l.logFile.Sync() // Ensure all writes are flushed
 		l.logFile.Close()
 	}
+	if l.console != nil {
+		l.console.Flush()
+	}
+}
+
+// shutdown releases the instance lock, flushes the log and exits
+func (l *Launcher) shutdown(code int) {
+	l.closeLogging()
+	l.lock.Release()
+	os.Exit(code)
 }
 
 // logAndSync logs a message and immediately syncs to disk
@@ -109,29 +249,80 @@ func (l *Launcher) logAndSync(format string, args ...interface{}) {
 }
 
 func (l *Launcher) updateProgress(value int, status string) {
-	l.progress = value
-	l.status = status
+	l.events.SetProgress(value, status)
+}
 
-	msg := fmt.Sprintf(`{"progress": %d, "status": "%s"}`, value, status)
-	for client := range l.clients {
-		select {
-		case client <- msg:
-		default:
-		}
-	}
+// updateStatus shows a message on the splash page without moving the progress bar
+func (l *Launcher) updateStatus(status string) {
+	l.events.SetStatus(status)
 }
 
 func (l *Launcher) sendRedirect() {
-	msg := `{"redirect": "http://localhost:3000/dashboard.html"}`
-	for client := range l.clients {
-		select {
-		case client <- msg:
-		default:
-		}
+	msg := fmt.Sprintf(`{"redirect": "http://localhost:%d/dashboard.html"}`, l.port)
+	l.events.Send(msg)
+}
+
+// sendTiming tells the splash page that a start phase finished
+func (l *Launcher) sendTiming() {
+	msg := `{"timing": true}`
+	l.events.Send(msg)
+}
+
+// sendProfilePicker asks the splash page to show the profile picker
+func (l *Launcher) sendProfilePicker() {
+	msg := `{"pickProfile": true}`
+	l.events.Send(msg)
+}
+
+// sendConfigRelocation asks the splash page to offer moving the config dir
+func (l *Launcher) sendConfigRelocation() {
+	msg := `{"relocateConfig": true}`
+	l.events.Send(msg)
+}
+
+// splashAddr is where the launcher serves its own progress page
+func (l *Launcher) splashAddr() string {
+	return fmt.Sprintf("127.0.0.1:%d", l.cfg.SplashPort)
+}
+
+// configDir is the ConfigPathManager directory holding user_configs and
+// user_data: --config-dir, else what app/.config_path points to
+func (l *Launcher) configDir() string {
+	if dir := l.cfg.ResolveConfigDir(l.exeDir); dir != "" {
+		return dir
+	}
+	return backup.ConfigDir(l.appDir)
+}
+
+// markPhase ends a start phase and updates the timing panel of the splash page
+func (l *Launcher) markPhase(name string) {
+	l.timing.Mark(name)
+	l.sendTiming()
+}
+
+// pause keeps a status message readable on the splash page; --fast skips it
+func (l *Launcher) pause(d time.Duration) {
+	l.timing.Pause(d)
+}
+
+// closeDelay keeps a fatal error visible on the splash page before exiting
+func (l *Launcher) closeDelay() {
+	if l.cfg.Headless {
+		return
 	}
+	l.updateProgress(100, fmt.Sprintf("❌ Launcher wird in %d Sekunden geschlossen...", int(l.cfg.CloseDelay.D().Seconds())))
+	time.Sleep(l.cfg.CloseDelay.D())
 }
 
 func (l *Launcher) checkNodeJS() error {
+	if l.cfg.NodePath != "" {
+		if _, err := os.Stat(l.cfg.NodePath); err != nil {
+			return fmt.Errorf("Node.js nicht gefunden unter %s", l.cfg.NodePath)
+		}
+		l.nodePath = l.cfg.NodePath
+		return nil
+	}
+
 	nodePath, err := exec.LookPath("node")
 	if err != nil {
 		return fmt.Errorf("Node.js ist nicht installiert")
@@ -158,26 +349,27 @@ func (l *Launcher) checkNodeModules() bool {
 	return info.IsDir()
 }
 
-func (l *Launcher) installDependencies() error {
+// installDependencies runs npm install in the app directory. With packages
+// ("name@range") only those are installed and package.json stays untouched.
+func (l *Launcher) installDependencies(packages ...string) error {
 	l.logger.Println("[INFO] Starting npm install...")
 	l.updateProgress(45, "npm install wird gestartet...")
-	time.Sleep(500 * time.Millisecond)
+	l.pause(500 * time.Millisecond)
 	
 	// Show initial warning about potential delay
 	l.updateProgress(45, "HINWEIS: npm install kann mehrere Minuten dauern, besonders bei langsamer Internetverbindung. Bitte warten...")
-	time.Sleep(2 * time.Second)
+	l.pause(2 * time.Second)
 	
-	var cmd *exec.Cmd
-	if runtime.GOOS == "windows" {
-		cmd = exec.Command("cmd", "/C", "npm", "install", "--cache", "false")
-		// Hide the npm install window on Windows using CREATE_NO_WINDOW flag
-		cmd.SysProcAttr = &syscall.SysProcAttr{
-			CreationFlags: createNoWindow,
-		}
-	} else {
-		cmd = exec.Command("npm", "install", "--cache", "false")
+	args := []string{"install"}
+	if len(packages) > 0 {
+		args = append(args, "--no-save")
 	}
-	
+	args = append(append(args, npmArgs(l.cfg, l.exeDir)...), packages...)
+	if dir := l.cfg.ResolveNpmCache(l.exeDir); dir != "" && npmcache.Warm(dir) {
+		l.logger.Printf("[INFO] Using npm cache %s (offline-first)\n", dir)
+	}
+	// Hidden on Windows; ranges like ^1.2.0 are quoted for cmd
+	cmd := procutil.NPM(args...)
 	cmd.Dir = l.appDir
 	
 	// Capture output for logging and progress updates
@@ -278,10 +470,167 @@ func (l *Launcher) installDependencies() error {
 	}
 	
 	l.logger.Println("[SUCCESS] npm install completed successfully")
+
+	// Remember which ABI the fresh node_modules were built for
+	if abi, err := nodeabi.RuntimeABI(l.nodePath); err == nil {
+		if err := nodeabi.Record(l.appDir, abi); err != nil {
+			l.logger.Printf("[WARNING] Could not record Node.js ABI: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// staleDependencies returns the packages of package.json that are missing
+// in node_modules or do not satisfy their declared range, e.g. after a
+// plugin or an app update added them.
+func (l *Launcher) staleDependencies() []npmdeps.Stale {
+	stale, err := npmdeps.Check(l.appDir)
+	if err != nil {
+		l.logger.Printf("[WARNING] Could not compare package.json with node_modules: %v\n", err)
+		return nil
+	}
+	if len(stale) == 0 {
+		l.logger.Println("[INFO] node_modules matches package.json")
+		return nil
+	}
+	for _, s := range stale {
+		if s.Installed == "" {
+			l.logger.Printf("[WARNING] Dependency %s (%s) is not installed\n", s.Name, s.Spec)
+		} else {
+			l.logger.Printf("[WARNING] Dependency %s %s does not satisfy %s\n", s.Name, s.Installed, s.Spec)
+		}
+	}
+	return stale
+}
+
+// updateStaleDependencies installs only the stale packages, not the whole
+// tree.
+func (l *Launcher) updateStaleDependencies(stale []npmdeps.Stale) error {
+	names := make([]string, len(stale))
+	packages := make([]string, len(stale))
+	for i, s := range stale {
+		names[i] = s.Name
+		packages[i] = s.Arg()
+	}
+	l.updateProgress(40, fmt.Sprintf("Installiere fehlende oder veraltete Pakete: %s", strings.Join(names, ", ")))
+	l.backupConfig("npm-install")
+	if err := l.installDependencies(packages...); err != nil {
+		return err
+	}
+
+	// A spec npm resolves differently than declared would be installed on
+	// every start; report it once and start anyway
+	if stale, err := npmdeps.Check(l.appDir); err == nil {
+		for _, s := range stale {
+			l.logger.Printf("[WARNING] Dependency still does not match package.json: %s\n", s)
+		}
+	}
+	return nil
+}
+
+// checkNativeModules detects native modules built for another Node.js ABI
+// and rebuilds them before the server gets a chance to crash on them
+func (l *Launcher) checkNativeModules() error {
+	l.logger.Println("[INFO] Checking native module ABI...")
+
+	result, err := nodeabi.Check(l.nodePath, l.appDir)
+	if err != nil {
+		l.logger.Printf("[WARNING] ABI check skipped: %v\n", err)
+		return nil
+	}
+	l.logger.Printf("[INFO] Node.js ABI: %s (recorded: %s)\n", result.RuntimeABI, result.RecordedABI)
+
+	if !result.Mismatch {
+		if result.RecordedABI != result.RuntimeABI {
+			if err := nodeabi.Record(l.appDir, result.RuntimeABI); err != nil {
+				l.logger.Printf("[WARNING] Could not record Node.js ABI: %v\n", err)
+			}
+		}
+		return nil
+	}
+
+	l.logger.Printf("[WARNING] Native module ABI mismatch: %s\n", result.Reason)
+	l.updateProgress(81, "🔧 Auto-Fix: Native Module passen nicht zur Node.js Version - baue neu...")
+	l.pause(1 * time.Second)
+
+	l.backupConfig("npm-rebuild")
+	if err := l.rebuildNativeModules(); err != nil {
+		l.logger.Printf("[ERROR] Native module rebuild failed: %v\n", err)
+		l.logVisualStudioHints()
+		return fmt.Errorf("better-sqlite3 konnte nicht neu gebaut werden: %v", err)
+	}
+
+	// Verify that the rebuilt binary actually loads now. Without a verified
+	// result no ABI is recorded, so the next start checks again
+	result, err = nodeabi.Check(l.nodePath, l.appDir)
+	switch {
+	case err != nil:
+		l.logger.Printf("[WARNING] Could not verify rebuilt native modules: %v\n", err)
+	case result.CompiledABI != "":
+		l.logger.Printf("[ERROR] Native module still built for ABI %s after rebuild\n", result.CompiledABI)
+		l.logVisualStudioHints()
+		return fmt.Errorf("better-sqlite3 passt nach dem Neubau immer noch nicht zu Node.js")
+	default:
+		if err := nodeabi.Record(l.appDir, result.RuntimeABI); err != nil {
+			l.logger.Printf("[WARNING] Could not record Node.js ABI: %v\n", err)
+		}
+	}
+	l.logger.Println("[SUCCESS] Native modules rebuilt for current Node.js version")
+	l.updateProgress(81, "✅ Native Module neu gebaut!")
 	return nil
 }
 
-func (l *Launcher) startTool() (*exec.Cmd, error) {
+// rebuildNativeModules runs npm rebuild for the ABI-specific packages.
+// better-sqlite3's install script tries a prebuilt binary first and only
+// falls back to compiling with node-gyp when none is available.
+func (l *Launcher) rebuildNativeModules() error {
+	args := append([]string{"rebuild"}, nodeabi.NativeModules...)
+	l.logger.Printf("[INFO] Running npm %s\n", strings.Join(args, " "))
+
+	cmd := procutil.NPM(args...)
+	cmd.Dir = l.appDir
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("Failed to create stdout pipe: %v", err)
+	}
+	cmd.Stderr = cmd.Stdout
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("Failed to start npm rebuild: %v", err)
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		line := scanner.Text()
+		l.logger.Printf("[npm rebuild] %s\n", line)
+		if len(line) > 0 {
+			displayLine := line
+			if len(displayLine) > 120 {
+				displayLine = displayLine[:117] + "..."
+			}
+			l.updateProgress(81, fmt.Sprintf("npm rebuild: %s", displayLine))
+		}
+	}
+
+	return cmd.Wait()
+}
+
+// logVisualStudioHints explains how to get a working native build when
+// neither a prebuilt binary nor a local compile succeeded
+func (l *Launcher) logVisualStudioHints() {
+	l.logAndSync("[ERROR] ===========================================")
+	l.logAndSync("[ERROR] better-sqlite3 konnte nicht für deine Node.js Version gebaut werden")
+	l.logAndSync("[ERROR] Lösung 1: Node.js v20 LTS oder v22 installieren")
+	l.logAndSync("[ERROR]   -> https://nodejs.org/en/download/")
+	l.logAndSync("[ERROR] Lösung 2: Visual Studio Build Tools 2019+ installieren")
+	l.logAndSync("[ERROR]   -> https://visualstudio.microsoft.com/downloads/")
+	l.logAndSync("[ERROR]   -> Workload 'Desktop development with C++' wählen")
+	l.logAndSync("[ERROR] Danach: cd app && npm rebuild better-sqlite3")
+	l.logAndSync("[ERROR] ===========================================")
+}
+
+func (l *Launcher) startTool() (*serverProcess, error) {
 	launchJS := filepath.Join(l.appDir, "launch.js")
 	cmd := exec.Command(l.nodePath, launchJS)
 	cmd.Dir = l.appDir
@@ -297,7 +646,31 @@ func (l *Launcher) startTool() (*exec.Cmd, error) {
 		}
 		env = append(env, e)
 	}
+	env = append(env, l.cfg.EnvList()...)
 	env = append(env, "OPEN_BROWSER=false")
+	if l.guard != nil {
+		// Lets the server call the control API on behalf of the dashboard
+		env = append(env, "LTTH_LAUNCHER_URL=http://"+l.splashAddr(), "LTTH_LAUNCHER_TOKEN="+l.guard.Token())
+	}
+	configDir := l.configDir()
+	if l.cfg.ConfigDir != "" {
+		env = append(env, "LTTH_CONFIG_DIR="+configDir)
+	}
+	// Re-read on every start so a profile switched in the dashboard survives restarts
+	profile := profiles.Active(configDir)
+	if profile == "" {
+		l.serverMu.Lock()
+		profile = l.profile
+		l.serverMu.Unlock()
+	}
+	if profile != "" {
+		// The server opens the profile's database in the config dir itself
+		env = append(env, "LTTH_PROFILE="+profile)
+	}
+	if l.port != defaultServerPort {
+		// Only override PORT when we moved the server, so a PORT from .env keeps working
+		env = append(env, fmt.Sprintf("PORT=%d", l.port))
+	}
 	cmd.Env = env
 
 	// Redirect both stdout and stderr to log file only (not os.Stdout because GUI mode has no console)
@@ -305,12 +678,30 @@ func (l *Launcher) startTool() (*exec.Cmd, error) {
 		cmd.Stdout = l.logFile
 		cmd.Stderr = l.logFile
 	}
+	if l.console != nil {
+		// Headless: the server output also goes to the service log
+		serverErr := logsink.New(os.Stderr, logsink.UnderJournald(), logsink.PriorityWarning)
+		if l.logFile != nil {
+			cmd.Stdout = io.MultiWriter(l.logFile, l.console)
+			cmd.Stderr = io.MultiWriter(l.logFile, serverErr)
+		} else {
+			cmd.Stdout = l.console
+			cmd.Stderr = serverErr
+		}
+	}
 	// Note: We don't redirect stdin in GUI mode as there's no console
 
+	// Own process group so a stop reaches launch.js and server.js together
+	// and nothing is left behind when the launcher goes away
+	procutil.SetProcessGroup(cmd)
+
 	l.logAndSync("Starting Node.js server...")
 	l.logAndSync("Command: %s %s", l.nodePath, launchJS)
 	l.logAndSync("Working directory: %s", l.appDir)
 	l.logAndSync("OPEN_BROWSER environment variable set to: false")
+	if profile != "" {
+		l.logAndSync("Profile: %s (%s)", profile, profiles.DBPath(configDir, profile))
+	}
 	l.logAndSync("--- Node.js Server Output Start ---")
 
 	err := cmd.Start()
@@ -318,458 +709,2658 @@ func (l *Launcher) startTool() (*exec.Cmd, error) {
 		return nil, err
 	}
 
-	return cmd, nil
-}
+	proc := &serverProcess{cmd: cmd, started: time.Now(), done: make(chan struct{})}
+	go func() {
+		proc.err = cmd.Wait()
+		proc.code = cmd.ProcessState.ExitCode()
+		l.serverMu.Lock()
+		code := proc.code
+		l.lastExit = &code
+		l.serverMu.Unlock()
+		close(proc.done)
+	}()
 
-// checkServerHealth checks if the server is responding
-func (l *Launcher) checkServerHealth() bool {
-	return l.checkServerHealthOnPort(3000)
-}
+	l.serverMu.Lock()
+	l.server = proc
+	l.held = false
+	l.serverMu.Unlock()
 
-// checkServerHealthOnPort checks if the server is responding on a specific port
-func (l *Launcher) checkServerHealthOnPort(port int) bool {
-	client := &http.Client{
-		Timeout: 2 * time.Second,
+	select {
+	case l.changed <- struct{}{}:
+	default:
 	}
 
-	url := fmt.Sprintf("http://localhost:%d/dashboard.html", port)
-	resp, err := client.Get(url)
-	if err != nil {
-		return false
+	return proc, nil
+}
+
+// stopServer asks the server to shut down gracefully (SIGINT / CTRL_BREAK,
+// which server.js answers by closing the database and cloud sync) and kills
+// the whole process group if it is still running after the shutdown timeout
+func (l *Launcher) stopServer() {
+	l.serverMu.Lock()
+	proc := l.server
+	l.serverMu.Unlock()
+	if proc == nil {
+		return
 	}
-	defer resp.Body.Close()
 
-	return resp.StatusCode == 200
-}
+	select {
+	case <-proc.done:
+		return
+	default:
+	}
 
-// waitForServer waits for the server to be ready or timeout
-func (l *Launcher) waitForServer(timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
+	pid := proc.cmd.Process.Pid
+	timeout := l.cfg.ShutdownTimeout.D()
+	l.logAndSync("[INFO] Stopping Node.js server (PID %d, timeout %v)...", pid, timeout)
+	if err := procutil.InterruptGroup(pid); err != nil {
+		l.logAndSync("[WARNING] Could not signal server: %v", err)
+	}
 
-	for time.Now().Before(deadline) {
-		if l.checkServerHealth() {
-			return nil
+	select {
+	case <-proc.done:
+		l.logAndSync("[SUCCESS] Node.js server stopped gracefully")
+	case <-time.After(timeout):
+		l.logAndSync("[WARNING] Server did not stop within %v - killing process group", timeout)
+		if err := procutil.KillGroup(pid); err != nil {
+			l.logAndSync("[ERROR] Could not kill server: %v", err)
+		}
+		select {
+		case <-proc.done:
+		case <-time.After(5 * time.Second):
+			l.logAndSync("[ERROR] Server process %d did not exit after kill", pid)
 		}
-		time.Sleep(500 * time.Millisecond)
 	}
-
-	return fmt.Errorf("Server did not start within %v", timeout)
 }
 
-// autoFixEnvFile checks if .env exists and creates it from .env.example if missing
-func (l *Launcher) autoFixEnvFile() error {
-	envPath := filepath.Join(l.appDir, ".env")
-	envExamplePath := filepath.Join(l.appDir, ".env.example")
-	
-	// Check if .env already exists
-	if _, err := os.Stat(envPath); err == nil {
-		l.logger.Println("[INFO] .env file already exists")
+// restartServer stops the running server and starts a fresh one, e.g. so
+// edited .env values take effect
+func (l *Launcher) restartServer() error {
+	l.restartMu.Lock()
+	defer l.restartMu.Unlock()
+
+	l.serverMu.Lock()
+	started := l.server != nil
+	l.serverMu.Unlock()
+	if !started {
+		// Still starting up - the server will read the new settings anyway
+		l.logAndSync("[INFO] Server not started yet, restart not needed")
 		return nil
 	}
-	
-	// Check if .env.example exists
-	if _, err := os.Stat(envExamplePath); os.IsNotExist(err) {
-		l.logger.Println("[WARNING] .env.example not found, cannot auto-create .env")
-		return fmt.Errorf(".env.example not found")
-	}
-	
-	l.logger.Println("[AUTO-FIX] Creating .env from .env.example...")
-	l.updateProgress(85, "🔧 Auto-Fix: Erstelle .env Datei...")
-	
-	// Read .env.example
-	input, err := os.ReadFile(envExamplePath)
-	if err != nil {
-		l.logger.Printf("[ERROR] Failed to read .env.example: %v\n", err)
-		return err
-	}
-	
-	// Write to .env
-	err = os.WriteFile(envPath, input, 0644)
-	if err != nil {
-		l.logger.Printf("[ERROR] Failed to write .env: %v\n", err)
+
+	l.serverMu.Lock()
+	l.pending = nil // Whatever was queued is covered by this restart
+	l.serverMu.Unlock()
+
+	l.logAndSync("[INFO] Restarting Node.js server...")
+	l.stopServer()
+	l.logAndSync("--- Node.js Server Output End ---")
+	if _, err := l.startTool(); err != nil {
+		l.logAndSync("[ERROR] Restart failed to start server: %v", err)
 		return err
 	}
-	
-	l.logger.Println("[SUCCESS] .env file created successfully")
-	l.updateProgress(86, "✅ .env Datei erstellt!")
-	l.envFileFixed = true // Mark that we fixed the .env file
-	time.Sleep(1 * time.Second)
-	
+	l.serverMu.Lock()
+	l.restarts++
+	l.serverMu.Unlock()
 	return nil
 }
 
-// checkPortAvailable checks if a port is available
-func (l *Launcher) checkPortAvailable(port int) bool {
-	address := fmt.Sprintf("localhost:%d", port)
-	listener, err := net.Listen("tcp", address)
-	if err != nil {
-		return false
+// requestRestart restarts the server for an automatic reason (changed
+// settings, crash recovery, ...). Unless the restart is critical it is queued
+// while the streamer is live on TikTok and runs once the connection drops.
+// It returns true if the restart was deferred.
+func (l *Launcher) requestRestart(reason string, critical bool) bool {
+	if !critical {
+		if live, username := l.isLive(); live {
+			l.serverMu.Lock()
+			queued := l.pending != nil
+			if !queued {
+				l.pending = &pendingRestart{Since: time.Now().Format(time.RFC3339), Username: username}
+			}
+			l.pending.Reasons = append(l.pending.Reasons, reason)
+			l.serverMu.Unlock()
+
+			l.logAndSync("[INFO] Streamer %s is live - restart (%s) deferred until the stream ends", username, reason)
+			l.updateStatus("⏳ Neustart ausstehend - wird nach dem Stream ausgeführt")
+			if !queued {
+				go l.runPendingRestart()
+			}
+			return true
+		}
 	}
-	listener.Close()
-	return true
+
+	l.logAndSync("[INFO] Restart requested: %s", reason)
+	go l.restartServer()
+	return false
 }
 
-// autoFixPort checks if port 3000 is available and logs status
-func (l *Launcher) autoFixPort() {
-	l.logger.Println("[INFO] Checking if port 3000 is available...")
-	
-	if l.checkPortAvailable(3000) {
-		l.logger.Println("[SUCCESS] Port 3000 is available")
+// runPendingRestart polls the server until the LIVE connection is gone and
+// then runs the queued restart
+func (l *Launcher) runPendingRestart() {
+	ticker := time.NewTicker(l.cfg.LiveCheckInterval.D())
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if l.stopping.Load() {
+			return
+		}
+		l.serverMu.Lock()
+		pending, held := l.pending, l.held
+		if held {
+			// Stopped via the control API - the next start reads the new settings
+			l.pending = nil
+		}
+		l.serverMu.Unlock()
+		if pending == nil || held {
+			return
+		}
+		if live, _ := l.isLive(); live {
+			continue
+		}
+		l.logAndSync("[INFO] Stream ended - running deferred restart (%s)", strings.Join(pending.Reasons, ", "))
+		l.restartServer()
 		return
 	}
-	
-	l.logger.Println("[WARNING] Port 3000 is already in use")
-	l.updateProgress(87, "⚠️ Port 3000 belegt - Server wird alternativen Port nutzen")
-	time.Sleep(2 * time.Second)
-	
-	// Check if server is already running on 3000
-	if l.checkServerHealthOnPort(3000) {
-		l.logger.Println("[INFO] Server is already running on port 3000")
-		l.updateProgress(88, "ℹ️ Server läuft bereits auf Port 3000")
-		time.Sleep(2 * time.Second)
-	}
 }
 
-func (l *Launcher) runLauncher() {
-	time.Sleep(1 * time.Second) // Give browser time to load
-
-	// Phase 1: Check Node.js (0-20%)
-	l.updateProgress(0, "Prüfe Node.js Installation...")
-	l.logAndSync("[Phase 1] Checking Node.js installation...")
-	time.Sleep(500 * time.Millisecond)
-
-	err := l.checkNodeJS()
+// isLive reports whether the server is connected to a TikTok LIVE. A server
+// that does not answer is not streaming; a rate-limited one might be.
+func (l *Launcher) isLive() (bool, string) {
+	st, err := serverapi.GetStatus(l.port)
+	if errors.Is(err, serverapi.ErrRateLimited) {
+		return true, ""
+	}
 	if err != nil {
-		l.logAndSync("[ERROR] Node.js check failed: %v", err)
-		l.updateProgress(0, "FEHLER: Node.js ist nicht installiert!")
-		time.Sleep(5 * time.Second)
-		l.closeLogging()
-		os.Exit(1)
+		return false, ""
 	}
+	return st.IsConnected, st.Username
+}
 
-	l.updateProgress(10, "Node.js gefunden...")
-	l.logAndSync("[SUCCESS] Node.js found at: %s", l.nodePath)
-	time.Sleep(300 * time.Millisecond)
-
-	version := l.getNodeVersion()
-	l.updateProgress(20, fmt.Sprintf("Node.js Version: %s", version))
-	l.logger.Printf("[INFO] Node.js version: %s\n", version)
-	time.Sleep(300 * time.Millisecond)
-
-	// Phase 2: Find directories (20-30%)
-	l.updateProgress(25, "Prüfe App-Verzeichnis...")
-	l.logger.Printf("[Phase 2] Checking app directory: %s\n", l.appDir)
-	time.Sleep(300 * time.Millisecond)
-
-	if _, err := os.Stat(l.appDir); os.IsNotExist(err) {
-		l.logger.Printf("[ERROR] App directory not found: %s\n", l.appDir)
-		l.updateProgress(25, "FEHLER: app Verzeichnis nicht gefunden")
-		time.Sleep(5 * time.Second)
-		l.closeLogging()
-		os.Exit(1)
+// runWatchdog keeps probing the server after startup. A process can be
+// alive but deadlocked (e.g. stuck in a synchronous better-sqlite3 call), so
+// repeated failed or slow answers restart it after writing a snapshot.
+func (l *Launcher) runWatchdog() {
+	interval := l.cfg.WatchdogInterval.D()
+	if interval == 0 {
+		l.logAndSync("[INFO] Watchdog disabled")
+		return
 	}
+	threshold := l.cfg.WatchdogLatency.D()
+	tracker := &watchdog.Tracker{Failures: l.cfg.WatchdogFailures, Latency: threshold}
+	l.serverMu.Lock()
+	l.watchdog.Enabled = true
+	l.serverMu.Unlock()
+	l.logAndSync("[INFO] Watchdog active: every %v, restart after %d failed or slower than %v answers", interval, tracker.Failures, threshold)
 
-	l.updateProgress(30, "App-Verzeichnis gefunden...")
-	l.logger.Printf("[SUCCESS] App directory exists: %s\n", l.appDir)
-	time.Sleep(300 * time.Millisecond)
+	var armed *serverProcess
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if l.stopping.Load() {
+			return
+		}
+		l.serverMu.Lock()
+		proc := l.server
+		l.serverMu.Unlock()
+		if proc == nil {
+			continue
+		}
+		select {
+		case <-proc.done:
+			// Stopped or in the middle of a restart
+			continue
+		default:
+		}
+
+		latency, err := serverapi.Ping(l.port, healthPath, 2*threshold)
+		if proc != armed {
+			if err != nil && time.Since(proc.started) < l.cfg.HealthTimeout.D() {
+				// Restarted server is still starting up
+				continue
+			}
+			armed = proc
+			tracker.Reset()
+		}
+
+		trip := tracker.Observe(latency, err)
+		l.serverMu.Lock()
+		l.watchdog.Strikes = tracker.Strikes()
+		l.watchdog.LastLatencyMs = latency.Milliseconds()
+		l.watchdog.LastError = ""
+		if err != nil {
+			l.watchdog.LastError = err.Error()
+		}
+		l.serverMu.Unlock()
+
+		switch {
+		case err != nil:
+			l.logAndSync("[WARNING] Watchdog: server did not answer: %v", err)
+		case latency > threshold:
+			l.logAndSync("[WARNING] Watchdog: server answered after %v (limit %v)", latency.Round(time.Millisecond), threshold)
+		}
+		if trip {
+			reason := fmt.Sprintf("Server reagiert nicht (%d fehlgeschlagene Prüfungen)", tracker.Failures)
+			if !l.watchdogRestart(proc, reason, latency, err) {
+				return
+			}
+		}
+	}
+}
+
+// watchdogRestart saves a diagnostic snapshot and restarts a hung or
+// crashed server. Such a server is not streaming properly anyway, so the
+// restart is critical and not deferred for a running LIVE. Past the restart
+// limit it stops the server instead, so the launcher exits with a failure,
+// and returns false.
+func (l *Launcher) watchdogRestart(proc *serverProcess, reason string, latency time.Duration, probeErr error) bool {
+	l.serverMu.Lock()
+	allowed := l.limit.Allow(time.Now())
+	if !allowed {
+		l.watchdog.GaveUp = true
+	}
+	l.serverMu.Unlock()
+	if !allowed {
+		l.logAndSync("[ERROR] Watchdog: %s - %d automatic restarts within the last hour, giving up", reason, l.cfg.WatchdogRestarts)
+		l.updateStatus("❌ Server reagiert wiederholt nicht - Launcher wird beendet")
+		l.stopServer()
+		return false
+	}
+	l.logAndSync("[ERROR] Watchdog: %s - restarting server", reason)
+
+	snap := &watchdog.Snapshot{
+		Time:        time.Now(),
+		Reason:      reason,
+		PID:         proc.cmd.Process.Pid,
+		LastLatency: latency.Round(time.Millisecond).String(),
+	}
+	if probeErr != nil {
+		snap.LastError = probeErr.Error()
+	}
+	if health, err := serverapi.ConnectionHealth(l.port); err != nil {
+		snap.HealthError = err.Error()
+	} else {
+		snap.ConnectionHealth = health
+	}
+	if l.logFile != nil {
+		snap.LogTail, _ = watchdog.TailLines(l.logFile.Name(), 50)
+	}
+	path, err := snap.Write(filepath.Join(l.appDir, "logs"))
+	if err != nil {
+		l.logAndSync("[WARNING] Could not write watchdog snapshot: %v", err)
+	} else {
+		l.logAndSync("[INFO] Watchdog snapshot written to %s", path)
+	}
+
+	l.serverMu.Lock()
+	l.watchdog.Restarts++
+	l.watchdog.LastSnapshot = path
+	l.serverMu.Unlock()
+
+	// Synchronous, so the watchdog does not probe the server being stopped
+	l.restartServer()
+	return true
+}
+
+// followRestart returns the server that replaced proc if proc exited
+// because of a restart, or nil if it simply exited. held reports that the
+// server was stopped via the control API and the launcher should wait.
+func (l *Launcher) followRestart(proc *serverProcess) (next *serverProcess, held bool) {
+	// Blocks while a restart is between stopping and starting
+	l.restartMu.Lock()
+	defer l.restartMu.Unlock()
+
+	l.serverMu.Lock()
+	defer l.serverMu.Unlock()
+	if l.server != proc {
+		return l.server, false
+	}
+	return nil, l.held
+}
+
+// handleSignals forwards SIGINT/SIGTERM (and console close on Windows, which
+// Go reports as SIGTERM) to the server before the launcher exits
+func (l *Launcher) handleSignals() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		l.stopping.Store(true)
+		l.logAndSync("[INFO] Received %v - shutting down...", sig)
+		l.updateStatus("🛑 Launcher wird beendet - Server wird gestoppt...")
+		l.stopServer()
+		l.shutdown(exitOK)
+	}()
+}
+
+// checkServerHealth checks if the server is responding
+func (l *Launcher) checkServerHealth() bool {
+	return l.checkServerHealthOnPort(l.port)
+}
+
+// pluginReadyTimeout bounds the wait for the plugin loader after the first
+// health response. The server loads its plugins before it listens, so the
+// wait is normally over at once.
+const pluginReadyTimeout = 30 * time.Second
+
+// waitForPlugins waits until the server reports its plugins as loaded.
+// Servers without /api/init-state are not waited for.
+func (l *Launcher) waitForPlugins() {
+	deadline := time.Now().Add(pluginReadyTimeout)
+	for {
+		st, err := serverapi.GetInitState(l.port)
+		switch {
+		case err == nil && st.PluginsReady():
+			l.markPhase(startprofile.Plugins)
+			return
+		case err != nil && !errors.Is(err, serverapi.ErrRateLimited):
+			l.logger.Printf("[INFO] Server does not report plugin readiness: %v\n", err)
+			return
+		case time.Now().After(deadline):
+			l.logger.Printf("[WARNING] Plugins not ready after %v, continuing\n", pluginReadyTimeout)
+			l.markPhase(startprofile.Plugins)
+			return
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+// recordStartProfile logs the phase times of this start and adds them to
+// the history in the config directory
+func (l *Launcher) recordStartProfile() startprofile.Run {
+	run := l.timing.Finish()
+	for _, p := range run.Phases {
+		l.logger.Printf("[INFO] Startup phase %-9s %6d ms (+%d ms pauses)\n", p.Name, p.Ms, p.PauseMs)
+	}
+	l.logger.Printf("[INFO] Startup took %d ms, %d ms of it pauses for the splash page\n", run.TotalMs, run.PauseMs())
+	if err := startprofile.Record(l.configDir(), run); err != nil {
+		l.logger.Printf("[WARNING] Could not record startup profile: %v\n", err)
+	}
+	return run
+}
+
+// startProfileView is the JSON answer of GET /api/launcher/startup-profile
+// and the data of the splash page's timing panel
+type startProfileView struct {
+	Phases      []phaseTiming `json:"phases"`
+	TotalMs     int64         `json:"totalMs"`
+	PauseMs     int64         `json:"pauseMs"`
+	Fast        bool          `json:"fast"`
+	LastTotalMs int64         `json:"lastTotalMs,omitempty"` // previous start, 0 on the first one
+}
+
+type phaseTiming struct {
+	Name    string `json:"name"`
+	Label   string `json:"label"`
+	Ms      int64  `json:"ms"`
+	PauseMs int64  `json:"pauseMs,omitempty"`
+	LastMs  *int64 `json:"lastMs,omitempty"` // same phase in the previous start
+}
+
+// startProfile compares the phases measured so far with the previous start
+func (l *Launcher) startProfile() startProfileView {
+	run := l.timing.Snapshot()
+	view := startProfileView{TotalMs: run.TotalMs, PauseMs: run.PauseMs(), Fast: run.Fast, Phases: []phaseTiming{}}
+	last := map[string]int64{}
+	if l.lastStart != nil {
+		view.LastTotalMs = l.lastStart.TotalMs
+		for _, p := range l.lastStart.Phases {
+			last[p.Name] = p.Ms
+		}
+	}
+	for _, p := range run.Phases {
+		t := phaseTiming{Name: p.Name, Label: startprofile.Label(p.Name), Ms: p.Ms, PauseMs: p.PauseMs}
+		if ms, ok := last[p.Name]; ok {
+			t.LastMs = &ms
+		}
+		view.Phases = append(view.Phases, t)
+	}
+	return view
+}
+
+// checkServerHealthOnPort checks if the server is responding on a specific port
+func (l *Launcher) checkServerHealthOnPort(port int) bool {
+	client := &http.Client{
+		Timeout: 2 * time.Second,
+	}
+
+	url := fmt.Sprintf("http://localhost:%d%s", port, healthPath)
+	resp, err := client.Get(url)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == 200
+}
+
+// waitForServer waits for the server to be ready or timeout
+func (l *Launcher) waitForServer(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		if l.checkServerHealth() {
+			return nil
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	return fmt.Errorf("Server did not start within %v", timeout)
+}
+
+// autoFixEnvFile checks if .env exists and creates it from .env.example if missing
+func (l *Launcher) autoFixEnvFile() error {
+	envPath := filepath.Join(l.appDir, ".env")
+	envExamplePath := filepath.Join(l.appDir, ".env.example")
+	
+	// Check if .env already exists
+	if _, err := os.Stat(envPath); err == nil {
+		l.logger.Println("[INFO] .env file already exists")
+		return l.mergeEnvFile()
+	}
+	
+	// Check if .env.example exists
+	if _, err := os.Stat(envExamplePath); os.IsNotExist(err) {
+		l.logger.Println("[WARNING] .env.example not found, cannot auto-create .env")
+		return fmt.Errorf(".env.example not found")
+	}
+	
+	l.logger.Println("[AUTO-FIX] Creating .env from .env.example...")
+	l.updateProgress(85, "🔧 Auto-Fix: Erstelle .env Datei...")
+	
+	// Read .env.example
+	input, err := os.ReadFile(envExamplePath)
+	if err != nil {
+		l.logger.Printf("[ERROR] Failed to read .env.example: %v\n", err)
+		return err
+	}
+	
+	// Write to .env
+	err = os.WriteFile(envPath, input, 0644)
+	if err != nil {
+		l.logger.Printf("[ERROR] Failed to write .env: %v\n", err)
+		return err
+	}
+	
+	l.logger.Println("[SUCCESS] .env file created successfully")
+	if err := os.WriteFile(filepath.Join(l.appDir, envBaseFile), input, 0644); err != nil {
+		l.logger.Printf("[WARNING] Could not save %s: %v\n", envBaseFile, err)
+	}
+	l.updateProgress(86, "✅ .env Datei erstellt!")
+	l.envFileFixed = true // Mark that we fixed the .env file
+	l.pause(1 * time.Second)
+	
+	return nil
+}
+
+// backupConfig snapshots the user's configuration (.env, user_configs,
+// user_data and the ConfigPathManager directory) before a step that changes
+// the app tree. A failed backup is logged but does not block the step.
+func (l *Launcher) backupConfig(reason string) {
+	if l.cfg.BackupKeep == 0 {
+		return
+	}
+	dir := l.cfg.ResolveBackupDir(l.exeDir)
+	src := backup.Sources{AppDir: l.appDir, ConfigDir: l.configDir()}
+	l.updateStatus("💾 Sichere Einstellungen...")
+	path, err := backup.Create(dir, src, reason, time.Now())
+	if err != nil {
+		l.logAndSync("[WARNING] Configuration backup before %s failed: %v", reason, err)
+		return
+	}
+	if path == "" {
+		l.logAndSync("[INFO] Nothing to back up before %s", reason)
+		return
+	}
+	l.logAndSync("[INFO] Configuration backed up to %s", path)
+	removed, err := backup.Prune(dir, l.cfg.BackupKeep)
+	if err != nil {
+		l.logAndSync("[WARNING] Could not remove old backups: %v", err)
+	}
+	for _, p := range removed {
+		l.logAndSync("[INFO] Removed old backup %s", filepath.Base(p))
+	}
+}
+
+// mergeEnvFile adds keys that a newer .env.example introduced to the user's
+// .env, keeping their values and comments
+func (l *Launcher) mergeEnvFile() error {
+	envPath := filepath.Join(l.appDir, ".env")
+	examplePath := filepath.Join(l.appDir, ".env.example")
+	basePath := filepath.Join(l.appDir, envBaseFile)
+
+	exampleData, err := os.ReadFile(examplePath)
+	if err != nil {
+		l.logger.Println("[INFO] .env.example not found, skipping .env merge")
+		return nil
+	}
+	example := dotenv.Parse(exampleData)
+
+	user, err := dotenv.ReadFile(envPath)
+	if err != nil {
+		return fmt.Errorf("cannot read .env: %v", err)
+	}
+
+	// Without a base (installs older than the merge) every missing key counts as new
+	base, err := dotenv.ReadFile(basePath)
+	if err != nil {
+		base = nil
+	}
+
+	result := dotenv.Merge(base, example, user, time.Now())
+	if result.Changed() {
+		l.logger.Printf("[AUTO-FIX] Merging .env.example into .env (added: %v, updated defaults: %v)\n", result.Added, result.Updated)
+		l.backupConfig("env-merge")
+		if err := dotenv.WriteFile(envPath, user); err != nil {
+			return fmt.Errorf("cannot write .env: %v", err)
+		}
+		l.updateProgress(85, fmt.Sprintf("🔧 Auto-Fix: %d neue Einstellung(en) in .env übernommen", len(result.Added)+len(result.Updated)))
+		l.pause(1 * time.Second)
+	} else {
+		l.logger.Println("[INFO] .env is up to date with .env.example")
+	}
+
+	if err := os.WriteFile(basePath, exampleData, 0644); err != nil {
+		l.logger.Printf("[WARNING] Could not save %s: %v\n", envBaseFile, err)
+	}
+	return nil
+}
+
+// checkEnvFile validates known .env keys so typos are explained here
+// instead of crashing the server. Only critical problems are returned.
+func (l *Launcher) checkEnvFile() error {
+	env, err := dotenv.ReadFile(filepath.Join(l.appDir, ".env"))
+	if err != nil {
+		return nil
+	}
+
+	var critical []string
+	for _, p := range dotenv.Validate(env) {
+		if p.Critical {
+			l.logger.Printf("[ERROR] .env: %s\n", p)
+			critical = append(critical, p.String())
+			continue
+		}
+		l.logger.Printf("[WARNING] .env: %s\n", p)
+		l.updateProgress(87, fmt.Sprintf("⚠️ .env: %s", p))
+		l.pause(2 * time.Second)
+	}
+	if len(critical) > 0 {
+		return fmt.Errorf("ungültige Einstellung in app/.env: %s", strings.Join(critical, "; "))
+	}
+
+	// Follow a PORT from .env unless the launcher was told otherwise
+	if value, ok := env.Get("PORT"); ok && value != "" && l.cfg.ServerPort == defaultServerPort {
+		if port, err := strconv.Atoi(value); err == nil {
+			l.port = port
+		}
+	}
+	return nil
+}
+
+// checkPlugins reports broken manifests and missing dependencies of the
+// enabled plugins before the loader fails on them. The server still starts,
+// it just runs without those plugins.
+func (l *Launcher) checkPlugins() {
+	list, err := plugins.List(filepath.Join(l.appDir, "plugins"))
+	if err != nil {
+		l.logger.Printf("[WARNING] Plugin check skipped: %v\n", err)
+		return
+	}
+
+	broken := 0
+	for _, p := range list {
+		if !p.Enabled {
+			continue
+		}
+		for _, prob := range p.Problems {
+			if !prob.Critical {
+				l.logger.Printf("[WARNING] Plugin %s: %s\n", p.ID, prob)
+				continue
+			}
+			l.logger.Printf("[ERROR] Plugin %s: %s\n", p.ID, prob)
+			l.updateProgress(88, fmt.Sprintf("⚠️ Plugin %s: %s", p.ID, prob))
+			l.pause(2 * time.Second)
+		}
+		if p.Error != "" {
+			broken++
+		}
+	}
+	if broken > 0 {
+		l.logger.Printf("[WARNING] %d plugin(s) will fail to load\n", broken)
+		l.updateProgress(88, fmt.Sprintf("⚠️ %d Plugin(s) werden nicht geladen - Details unter \"Plugins\" auf der Startseite", broken))
+		l.pause(2 * time.Second)
+	}
+}
+
+// checkDiskAndAccess runs the preflight checks. The first critical problem
+// is returned with its fix; for an unwritable config directory the splash
+// page offers a writable one instead.
+func (l *Launcher) checkDiskAndAccess() error {
+	l.logger.Println("[INFO] Checking disk space and write access...")
+	configDir := l.configDir()
+	var fatal error
+	for _, p := range preflight.Check(l.appDir, configDir, !l.checkNodeModules()) {
+		switch {
+		case p.Critical:
+			l.logAndSync("[ERROR] Preflight: %s", p)
+			if fatal == nil {
+				fatal = errors.New(p.Message)
+			}
+		case p.Dir == configDir:
+			l.logAndSync("[WARNING] Preflight: %s", p)
+			if fatal == nil {
+				l.offerConfigRelocation(configDir)
+			}
+		default:
+			l.logAndSync("[WARNING] Preflight: %s", p)
+			l.updateStatus("⚠️ " + p.Message)
+			l.pause(2 * time.Second)
+		}
+	}
+	return fatal
+}
+
+// offerConfigRelocation suggests a writable config directory on the splash
+// page and waits until it is accepted or declined. With --config-dir or
+// without a browser the problem is only reported.
+func (l *Launcher) offerConfigRelocation(current string) {
+	target := relocationTarget(current, l.exeDir)
+	if l.cfg.ConfigDir != "" || target == "" || !l.cfg.OpenBrowser {
+		l.updateStatus(fmt.Sprintf("⚠️ Kein Schreibzugriff auf den Konfigurationsordner %s", current))
+		l.pause(2 * time.Second)
+		return
+	}
+
+	l.logAndSync("[INFO] Offering to move the config directory to %s", target)
+	l.updateStatus("📁 Konfigurationsordner verlegen?")
+	l.serverMu.Lock()
+	l.relocation = &configRelocation{Offered: true, From: current, To: target, until: time.Now().Add(relocateTimeout)}
+	l.serverMu.Unlock()
+	l.sendConfigRelocation()
+
+	select {
+	case <-l.relocated:
+	case <-time.After(relocateTimeout):
+		l.logAndSync("[WARNING] No answer, starting without moving the config directory")
+	}
+	l.serverMu.Lock()
+	l.relocation = nil
+	l.serverMu.Unlock()
+}
+
+// relocationTarget is the first writable candidate for a new config
+// directory: the platform default, then folders in the home and the
+// launcher directory
+func relocationTarget(current, exeDir string) string {
+	home, _ := os.UserHomeDir()
+	for _, dir := range []string{backup.DefaultConfigDir(), filepath.Join(home, "LTTH-Config"), filepath.Join(exeDir, "config")} {
+		if filepath.Clean(dir) != filepath.Clean(current) && preflight.Writable(dir) == nil {
+			return dir
+		}
+	}
+	return ""
+}
+
+// currentRelocation answers GET /api/launcher/config-relocation
+func (l *Launcher) currentRelocation() configRelocation {
+	l.serverMu.Lock()
+	defer l.serverMu.Unlock()
+	if l.relocation == nil {
+		return configRelocation{}
+	}
+	offer := *l.relocation
+	offer.RemainingSeconds = int(time.Until(offer.until).Round(time.Second).Seconds())
+	return offer
+}
+
+// relocateConfig answers POST /api/launcher/config-dir while the move is
+// offered: dir receives a copy of the old directory and is written to
+// app/.config_path, skip=1 continues without moving.
+func (l *Launcher) relocateConfig(r *http.Request) error {
+	offer := l.currentRelocation()
+	if !offer.Offered {
+		return errors.New("Es wird gerade keine Verlegung angeboten")
+	}
+	if r.FormValue("skip") == "" {
+		dir := strings.TrimSpace(r.FormValue("dir"))
+		if !filepath.IsAbs(dir) {
+			return fmt.Errorf("Bitte einen vollständigen Pfad angeben")
+		}
+		dir = filepath.Clean(dir)
+		if err := preflight.Relocate(offer.From, dir); err != nil {
+			return fmt.Errorf("%s ist nicht verwendbar: %v", dir, err)
+		}
+		if err := backup.SetConfigDir(l.appDir, dir); err != nil {
+			return err
+		}
+		l.logAndSync("[SUCCESS] Config directory moved to %s (app/.config_path)", dir)
+		l.updateStatus("✅ Konfigurationsordner verlegt")
+	} else {
+		l.logAndSync("[INFO] Moving the config directory was declined")
+	}
+	select {
+	case l.relocated <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// selectProfile decides which streamer profile the server starts with:
+// --profile wins, otherwise the splash page offers a picker when there is
+// more than one profile. Without a choice the last used profile stays active.
+func (l *Launcher) selectProfile() {
+	dir := l.configDir()
+	name := l.cfg.Profile
+	if name == "" {
+		list, err := profiles.List(dir)
+		if err != nil {
+			l.logAndSync("[WARNING] Could not list profiles in %s: %v", profiles.Dir(dir), err)
+			return
+		}
+		if len(list) < 2 || !l.cfg.OpenBrowser || l.cfg.ProfileTimeout == 0 {
+			return
+		}
+		if name = l.pickProfile(); name == "" {
+			l.logAndSync("[INFO] No profile chosen, keeping the last active one")
+			return
+		}
+	}
+
+	l.serverMu.Lock()
+	l.profile = name
+	l.serverMu.Unlock()
+	if name == profiles.Active(dir) {
+		l.logAndSync("[INFO] Profile: %s", name)
+		return
+	}
+	if err := profiles.SetActive(dir, name); err != nil {
+		l.logAndSync("[WARNING] Could not switch to profile %s: %v", name, err)
+		return
+	}
+	l.logAndSync("[INFO] Switched to profile %s", name)
+}
+
+// pickProfile shows the picker on the splash page and waits for a choice
+func (l *Launcher) pickProfile() string {
+	timeout := l.cfg.ProfileTimeout.D()
+	l.logAndSync("[INFO] Waiting %v for a profile choice on the splash page...", timeout)
+	l.updateStatus("👤 Profil auswählen...")
+	l.serverMu.Lock()
+	l.pickUntil = time.Now().Add(timeout)
+	l.serverMu.Unlock()
+	l.picking.Store(true)
+	defer l.picking.Store(false)
+	l.sendProfilePicker()
+
+	select {
+	case name := <-l.picked:
+		return name
+	case <-time.After(timeout):
+		return ""
+	}
+}
+
+// profileList is the JSON answer of GET /api/launcher/profiles
+type profileList struct {
+	Profiles         []profiles.Profile `json:"profiles"`
+	Active           string             `json:"active"`
+	Picking          bool               `json:"picking"` // the launcher waits for a choice
+	RemainingSeconds int                `json:"remainingSeconds"`
+}
+
+func (l *Launcher) listProfiles() (profileList, error) {
+	dir := l.configDir()
+	list, err := profiles.List(dir)
+	if list == nil {
+		list = []profiles.Profile{}
+	}
+	pl := profileList{Profiles: list, Active: profiles.Active(dir), Picking: l.picking.Load()}
+	if pl.Picking {
+		l.serverMu.Lock()
+		pl.RemainingSeconds = int(time.Until(l.pickUntil).Round(time.Second).Seconds())
+		l.serverMu.Unlock()
+	}
+	return pl, err
+}
+
+// switchProfile answers POST /api/launcher/profile. While the picker is
+// shown the choice is used for the first start, later it makes the server
+// restart with the new profile once the LIVE is over.
+func (l *Launcher) switchProfile(name string) error {
+	if !profiles.ValidName(name) {
+		return fmt.Errorf("Ungültiger Profilname %q", name)
+	}
+	if l.picking.Load() {
+		select {
+		case l.picked <- name:
+		default:
+		}
+		return nil
+	}
+	if !l.ready.Load() {
+		return errors.New("Launcher startet noch")
+	}
+	if err := profiles.SetActive(l.configDir(), name); err != nil {
+		return err
+	}
+	l.serverMu.Lock()
+	l.profile = name
+	held := l.held
+	l.serverMu.Unlock()
+	l.logAndSync("[INFO] Profile switched to %s via control API", name)
+	if !held {
+		// A held server picks the profile up on its next start
+		l.requestRestart("Profilwechsel: "+name, false)
+	}
+	return nil
+}
+
+// checkPortAvailable checks if a port is available
+func (l *Launcher) checkPortAvailable(port int) bool {
+	address := fmt.Sprintf("localhost:%d", port)
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return false
+	}
+	listener.Close()
+	return true
+}
+
+// autoFixPort checks if the server port is available. If it is held by a
+// stale LTTH server from this installation, that server is shut down
+// gracefully; any other program makes the launcher switch to a free port.
+func (l *Launcher) autoFixPort() {
+	l.logger.Printf("[INFO] Checking if port %d is available...\n", l.port)
+
+	if l.checkPortAvailable(l.port) {
+		l.logger.Printf("[SUCCESS] Port %d is available\n", l.port)
+		return
+	}
+
+	l.logger.Printf("[WARNING] Port %d is already in use\n", l.port)
+
+	owner, err := portowner.Find(l.port)
+	if err != nil {
+		l.logger.Printf("[WARNING] Could not resolve process on port %d: %v\n", l.port, err)
+	} else {
+		l.logger.Printf("[INFO] Port %d is held by %s: %s\n", l.port, owner, owner.Cmdline)
+	}
+
+	if owner != nil && owner.IsLTTHServer(l.appDir) {
+		l.updateProgress(87, fmt.Sprintf("⚠️ Port %d belegt von alter LTTH-Instanz (PID %d) - wird beendet...", l.port, owner.PID))
+		l.logger.Printf("[AUTO-FIX] Stopping stale LTTH server (PID %d)...\n", owner.PID)
+
+		if err := procutil.Terminate(owner.PID, 10*time.Second); err != nil {
+			l.logger.Printf("[WARNING] Could not stop PID %d: %v\n", owner.PID, err)
+		}
+
+		// Give the OS a moment to release the socket
+		for i := 0; i < 10 && !l.checkPortAvailable(l.port); i++ {
+			time.Sleep(500 * time.Millisecond)
+		}
+		if l.checkPortAvailable(l.port) {
+			l.logger.Printf("[SUCCESS] Port %d released by stale LTTH server\n", l.port)
+			l.updateProgress(88, fmt.Sprintf("✅ Alte LTTH-Instanz beendet - Port %d frei", l.port))
+			l.pause(1 * time.Second)
+			return
+		}
+		l.logger.Printf("[WARNING] Port %d still in use after stopping PID %d\n", l.port, owner.PID)
+	} else if owner != nil {
+		l.updateProgress(87, fmt.Sprintf("⚠️ Port %d belegt von %s", l.port, filepath.Base(owner.Exe)))
+	} else {
+		l.updateProgress(87, fmt.Sprintf("⚠️ Port %d belegt", l.port))
+	}
+	l.pause(2 * time.Second)
+
+	// Another program holds the port - move the server out of its way
+	port, err := portowner.FreePort(l.port+1, l.port+100)
+	if err != nil {
+		l.logger.Printf("[ERROR] %v\n", err)
+		return
+	}
+	l.logger.Printf("[AUTO-FIX] Using port %d instead of %d\n", port, l.port)
+	l.updateProgress(88, fmt.Sprintf("🔧 Auto-Fix: Server nutzt Port %d statt %d", port, l.port))
+	l.port = port
+	l.pause(1 * time.Second)
+}
+
+func (l *Launcher) runLauncher() {
+	l.pause(1 * time.Second) // Give browser time to load
+	l.markPhase(startprofile.Splash)
+
+	// Phase 1: Check Node.js (0-20%)
+	l.updateProgress(0, "Prüfe Node.js Installation...")
+	l.logAndSync("[Phase 1] Checking Node.js installation...")
+	l.pause(500 * time.Millisecond)
+
+	err := l.checkNodeJS()
+	if err != nil {
+		l.logAndSync("[ERROR] Node.js check failed: %v", err)
+		l.updateProgress(0, "FEHLER: Node.js ist nicht installiert!")
+		time.Sleep(5 * time.Second)
+		l.shutdown(exitFailure)
+	}
+
+	l.updateProgress(10, "Node.js gefunden...")
+	l.logAndSync("[SUCCESS] Node.js found at: %s", l.nodePath)
+	l.pause(300 * time.Millisecond)
+
+	version := strings.TrimSpace(l.getNodeVersion())
+	l.nodeVersion = version
+	l.updateProgress(20, fmt.Sprintf("Node.js Version: %s", version))
+	l.logger.Printf("[INFO] Node.js version: %s\n", version)
+	l.pause(300 * time.Millisecond)
+	l.markPhase(startprofile.Node)
+
+	// Phase 2: Find directories (20-30%)
+	l.updateProgress(25, "Prüfe App-Verzeichnis...")
+	l.logger.Printf("[Phase 2] Checking app directory: %s\n", l.appDir)
+	l.pause(300 * time.Millisecond)
+
+	if _, err := os.Stat(l.appDir); os.IsNotExist(err) {
+		l.logger.Printf("[ERROR] App directory not found: %s\n", l.appDir)
+		l.updateProgress(25, "FEHLER: app Verzeichnis nicht gefunden")
+		time.Sleep(5 * time.Second)
+		l.shutdown(exitFailure)
+	}
+
+	l.updateProgress(30, "App-Verzeichnis gefunden...")
+	l.logger.Printf("[SUCCESS] App directory exists: %s\n", l.appDir)
+	l.pause(300 * time.Millisecond)
+
+	// Phase 2.5: Disk space and write access, before npm install, log
+	// files and the server fail with less helpful errors
+	if err := l.checkDiskAndAccess(); err != nil {
+		l.updateProgress(30, fmt.Sprintf("FEHLER: %v", err))
+		l.closeDelay()
+		l.shutdown(exitFailure)
+	}
+	l.markPhase(startprofile.Preflight)
+
+	// Phase 3: Check and install dependencies (30-80%)
+	l.updateProgress(30, "Prüfe Abhängigkeiten...")
+	l.logger.Println("[Phase 3] Checking dependencies...")
+	l.pause(300 * time.Millisecond)
+
+	missing := !l.checkNodeModules()
+	var stale []npmdeps.Stale
+	if !missing {
+		stale = l.staleDependencies()
+	}
+	l.markPhase(startprofile.Deps)
+
+	var installErr error
+	switch {
+	case missing:
+		l.updateProgress(40, "Installiere Abhängigkeiten...")
+		l.logger.Println("[INFO] node_modules not found, installing dependencies...")
+		l.pause(500 * time.Millisecond)
+		l.updateProgress(45, "HINWEIS: npm install kann einige Minuten dauern, bitte das Fenster offen halten und warten")
+
+		l.backupConfig("npm-install")
+		installErr = l.installDependencies()
+	case len(stale) > 0:
+		installErr = l.updateStaleDependencies(stale)
+	default:
+		l.updateProgress(80, "Abhängigkeiten bereits installiert...")
+		l.logger.Println("[INFO] Dependencies already installed")
+	}
+	if installErr != nil {
+		l.logger.Printf("[ERROR] Dependency installation failed: %v\n", installErr)
+		l.updateProgress(45, fmt.Sprintf("FEHLER: %v", installErr))
+		time.Sleep(5 * time.Second)
+		l.shutdown(exitFailure)
+	}
+	if missing || len(stale) > 0 {
+		l.updateProgress(80, "Installation abgeschlossen!")
+		l.logger.Println("[SUCCESS] Dependencies installed successfully")
+	}
+	l.pause(300 * time.Millisecond)
+	l.markPhase(startprofile.Install)
+
+	// Phase 3.2: Make sure native modules match the installed Node.js
+	if err := l.checkNativeModules(); err != nil {
+		l.updateProgress(81, fmt.Sprintf("FEHLER: %v", err))
+		time.Sleep(2 * time.Second)
+		l.updateProgress(81, "💡 Installiere Node.js v20 LTS oder die Visual Studio Build Tools (Details in app/logs/)")
+		l.closeDelay()
+		l.shutdown(exitFailure)
+	}
+	l.markPhase(startprofile.Native)
+
+	// Phase 3.5: Auto-fix common issues (80-89%)
+	l.updateProgress(82, "Prüfe Konfiguration...")
+	l.logger.Println("[Phase 3.5] Auto-fixing common issues...")
+	l.pause(300 * time.Millisecond)
+	
+	// Auto-fix: Create .env file if missing, merge new keys otherwise
+	if err := l.autoFixEnvFile(); err != nil {
+		l.logger.Printf("[WARNING] Could not auto-fix .env: %v\n", err)
+	}
+
+	// Explain invalid values before the server trips over them
+	if err := l.checkEnvFile(); err != nil {
+		l.logger.Printf("[ERROR] %v\n", err)
+		l.updateProgress(87, fmt.Sprintf("FEHLER: %v", err))
+		time.Sleep(5 * time.Second)
+		l.closeDelay()
+		l.shutdown(exitFailure)
+	}
+
+	// Broken plugins only fail deep inside the plugin loader otherwise
+	l.checkPlugins()
+
+	// Auto-fix: Check port availability
+	l.autoFixPort()
+	
+	l.updateProgress(89, "Konfiguration geprüft!")
+	l.pause(300 * time.Millisecond)
+	l.markPhase(startprofile.AutoFix)
+
+	// Let the user choose who is streaming before the database is opened
+	l.selectProfile()
+	l.markPhase(startprofile.Profile)
+
+	// Phase 4: Start tool (90-100%)
+	l.updateProgress(90, "Starte Tool...")
+	l.logger.Println("[Phase 4] Starting Node.js server...")
+	l.pause(500 * time.Millisecond)
+
+	// Start the tool
+	proc, err := l.startTool()
+	if err != nil {
+		l.logger.Printf("[ERROR] Failed to start server: %v\n", err)
+		l.updateProgress(90, fmt.Sprintf("FEHLER beim Starten: %v", err))
+		l.updateProgress(90, "Prüfe bitte die Log-Datei in app/logs/ für Details.")
+		time.Sleep(30 * time.Second)
+		l.shutdown(exitFailure)
+	}
+	l.markPhase(startprofile.Spawn)
+
+	// Wait for server to be ready
+	l.updateProgress(93, "Warte auf Server-Start...")
+	healthTimeout := l.cfg.HealthTimeout.D()
+	l.logger.Printf("[INFO] Waiting for server health check (%v timeout)...\n", healthTimeout)
+	l.logger.Printf("[INFO] Checking if server responds on http://localhost:%d...\n", l.port)
+
+	// Check server health with process monitoring
+	healthCheckTimeout := time.After(healthTimeout)
+	healthCheckTicker := time.NewTicker(1 * time.Second)
+	defer healthCheckTicker.Stop()
+
+	serverReady := false
+	attemptCount := 0
+	lastLogTime := time.Now()
+	
+	for !serverReady {
+		select {
+		case <-proc.done:
+			if l.stopping.Load() {
+				// Stopped on purpose by handleSignals
+				return
+			}
+			if next, _ := l.followRestart(proc); next != nil {
+				// Restarted from the settings page while starting up
+				proc = next
+				continue
+			}
+			err := proc.err
+			// Process exited before server was ready
+			// Ensure log file is flushed to capture all server output
+			if l.logFile != nil {
+				l.logFile.Sync()
+				time.Sleep(100 * time.Millisecond) // Give a moment for any buffered writes
+			}
+			
+			l.logAndSync("--- Node.js Server Output End ---")
+			l.logAndSync("[ERROR] ===========================================")
+			l.logAndSync("[ERROR] Node.js process exited prematurely: %v", err)
+			l.logAndSync("[ERROR] Server crashed during startup!")
+			l.logAndSync("[ERROR] Check the server output above for the actual error")
+			l.logAndSync("[ERROR] ===========================================")
+			l.logAndSync("[ERROR] Häufige Ursachen:")
+			l.logAndSync("[ERROR]  - Fehlende .env Datei (kopiere .env.example zu .env)")
+			l.logAndSync("[ERROR]  - Port %d bereits belegt", l.port)
+			l.logAndSync("[ERROR]  - Fehlende Dependencies (führe 'npm install' aus)")
+			l.logAndSync("[ERROR]  - Syntax-Fehler im Code")
+			l.logAndSync("[ERROR] ===========================================")
+			
+			// Check if we just fixed the .env file - if so, retry once
+			if l.envFileFixed {
+				l.logAndSync("[AUTO-FIX] .env file was just created - attempting restart...")
+				l.updateProgress(95, "🔄 .env erstellt - starte Server neu...")
+				time.Sleep(3 * time.Second)
+				
+				// Mark that we already tried the fix
+				l.envFileFixed = false
+				
+				// Start server again
+				proc, err = l.startTool()
+				if err != nil {
+					l.logAndSync("[ERROR] Retry failed to start server: %v", err)
+				} else {
+					l.updateProgress(96, "🔄 Server neugestartet - warte auf Antwort...")
+					l.logAndSync("[INFO] Server restarted after .env fix - waiting for health check...")
+					
+					// Reset the ticker for another try
+					continue
+				}
+			}
+			
+			l.updateProgress(95, "⚠️ Server konnte nicht starten!")
+			time.Sleep(2 * time.Second)
+			l.updateProgress(96, "📋 Alle Auto-Fixes wurden versucht")
+			time.Sleep(2 * time.Second)
+			l.updateProgress(97, "💡 Prüfe app/logs/launcher_*.log für Details")
+			time.Sleep(2 * time.Second)
+			l.updateProgress(98, "💡 Oder führe manuell: cd app && npm install")
+			time.Sleep(2 * time.Second)
+			l.updateProgress(99, fmt.Sprintf("💡 Oder prüfe ob Port %d frei ist", l.port))
+			time.Sleep(2 * time.Second)
+			l.closeDelay()
+			l.shutdown(exitFailure)
+		case <-healthCheckTicker.C:
+			attemptCount++
+			
+			// Log progress every 5 seconds
+			if time.Since(lastLogTime) >= 5 * time.Second {
+				l.logger.Printf("[INFO] Health check attempt %d (waiting for server to respond)...\n", attemptCount)
+				l.updateProgress(93 + (attemptCount / 5), fmt.Sprintf("Warte auf Server... (Versuch %d)", attemptCount))
+				lastLogTime = time.Now()
+			}
+			
+			// Only our port: another LTTH instance or an unrelated app on a
+			// neighbouring port must not count as our server being ready
+			if l.checkServerHealthOnPort(l.port) {
+				l.logger.Printf("[SUCCESS] Server responded on port %d!\n", l.port)
+				serverReady = true
+			}
+		case <-healthCheckTimeout:
+			l.logger.Printf("[ERROR] Server health check timed out after %v\n", healthTimeout)
+			l.logger.Println("[ERROR] Server did not respond. Check the log above for error messages.")
+			l.logger.Println("[ERROR] ===========================================")
+			l.logger.Println("[ERROR] Mögliche Probleme:")
+			l.logger.Println("[ERROR]  - Server startet, aber hängt sich bei Initialisierung auf")
+			l.logger.Println("[ERROR]  - Dependencies werden geladen (kann lange dauern)")
+			l.logger.Println("[ERROR]  - Datenbank-Migration läuft")
+			l.logger.Printf("[ERROR]  - Port %d ist blockiert durch Firewall\n", l.port)
+			l.logger.Println("[ERROR] ===========================================")
+			
+			l.updateProgress(95, fmt.Sprintf("⏱️ Server-Start Timeout (%v)", healthTimeout))
+			time.Sleep(2 * time.Second)
+			l.updateProgress(96, "📋 Server antwortet nicht - prüfe app/logs/")
+			time.Sleep(2 * time.Second)
+			l.updateProgress(97, "💡 Server läuft evtl. noch im Hintergrund")
+			time.Sleep(2 * time.Second)
+			l.updateProgress(98, fmt.Sprintf("💡 Warte 2-3 Minuten und öffne localhost:%d", l.port))
+			time.Sleep(2 * time.Second)
+			l.closeDelay()
+			l.shutdown(exitFailure)
+		}
+	}
+
+	l.markPhase(startprofile.Health)
+	l.waitForPlugins()
+	run := l.recordStartProfile()
+
+	l.updateProgress(100, fmt.Sprintf("Server erfolgreich gestartet! (%s)", startprofile.Seconds(run.TotalMs)))
+	l.logger.Println("[SUCCESS] Server is running and healthy!")
+	l.pause(500 * time.Millisecond)
+	l.updateProgress(100, "Weiterleitung zum Dashboard...")
+	l.logger.Println("[INFO] Redirecting to dashboard...")
+	l.pause(500 * time.Millisecond)
+	l.sendRedirect()
+
+	// A second launch now opens the dashboard instead of the splash screen
+	if l.lock != nil {
+		if err := l.lock.SetURL(fmt.Sprintf("http://localhost:%d/dashboard.html", l.port)); err != nil {
+			l.logger.Printf("[WARNING] Could not update launcher.lock: %v\n", err)
+		}
+	}
+
+	// Stay resident as the server's parent so signals reach it and it is
+	// never orphaned; the launcher exits together with the server unless it
+	// was stopped through the control API or crashed and is restarted
+	l.ready.Store(true)
+	go l.runWatchdog()
+	go l.checkLauncherUpdate()
+
+	// The changelog shows what is new since this start from now on
+	if l.appVersion != "" && l.appVersion != l.lastVersion {
+		if err := changelog.RecordVersion(l.configDir(), l.appVersion); err != nil {
+			l.logger.Printf("[WARNING] Could not record app version: %v\n", err)
+		}
+	}
+	for {
+		<-proc.done
+		if l.stopping.Load() {
+			return
+		}
+		next, held := l.followRestart(proc)
+		if next != nil {
+			proc = next
+			continue
+		}
+		if held {
+			l.logAndSync("[INFO] Server stopped via control API - waiting for a start request")
+			<-l.changed
+			continue
+		}
+		l.serverMu.Lock()
+		gaveUp := l.watchdog.GaveUp
+		l.serverMu.Unlock()
+		if gaveUp || proc.code == 0 {
+			// Stopped by the watchdog, or the server shut down on purpose
+			break
+		}
+		// Crashed: restarted like a hung server, the restart limit ends a
+		// crash loop
+		reason := fmt.Sprintf("Server unerwartet beendet (Exit-Code %d)", proc.code)
+		if !l.watchdogRestart(proc, reason, 0, proc.err) {
+			break
+		}
+	}
+	l.logAndSync("--- Node.js Server Output End ---")
+	l.logAndSync("[INFO] Node.js server exited: %v", proc.err)
+	code := proc.code
+	l.serverMu.Lock()
+	gaveUp := l.watchdog.GaveUp
+	l.serverMu.Unlock()
+	if code < 0 || gaveUp {
+		// Killed by a signal, or stopped by the watchdog
+		code = exitFailure
+	}
+	l.shutdown(code)
+}
+
+// launcherStatus is the JSON answer of GET /api/launcher/status
+type launcherStatus struct {
+	State          string          `json:"state"` // starting, running, stopped
+	PID            int             `json:"pid,omitempty"`
+	StartedAt      string          `json:"startedAt,omitempty"`
+	UptimeSeconds  float64         `json:"uptimeSeconds"`
+	Port           int             `json:"port"`
+	Restarts       int             `json:"restarts"`
+	LastExitCode   *int            `json:"lastExitCode"`
+	PendingRestart *pendingRestart `json:"pendingRestart"`
+	Watchdog       watchdogStatus  `json:"watchdog"`
+	NodeVersion    string          `json:"nodeVersion"`
+	Profile        string          `json:"profile,omitempty"`
+	LauncherPID    int             `json:"launcherPid"`
+}
+
+func (l *Launcher) controlStatus() launcherStatus {
+	l.serverMu.Lock()
+	defer l.serverMu.Unlock()
+
+	st := launcherStatus{
+		State:          "starting",
+		Port:           l.port,
+		Restarts:       l.restarts,
+		LastExitCode:   l.lastExit,
+		PendingRestart: l.pending,
+		Watchdog:       l.watchdog,
+		NodeVersion:    l.nodeVersion,
+		Profile:        profiles.Active(l.configDir()),
+		LauncherPID:    os.Getpid(),
+	}
+	if proc := l.server; proc != nil {
+		select {
+		case <-proc.done:
+			st.State = "stopped"
+		default:
+			st.PID = proc.cmd.Process.Pid
+			st.StartedAt = proc.started.Format(time.RFC3339)
+			st.UptimeSeconds = time.Since(proc.started).Round(time.Second).Seconds()
+			if l.ready.Load() {
+				st.State = "running"
+			}
+		}
+	}
+	return st
+}
+
+// stopServerHeld stops the server without exiting the launcher, so it can
+// be started again through the control API
+func (l *Launcher) stopServerHeld() {
+	l.restartMu.Lock()
+	defer l.restartMu.Unlock()
+
+	l.serverMu.Lock()
+	l.held = true
+	l.serverMu.Unlock()
+	l.stopServer()
+	l.logAndSync("--- Node.js Server Output End ---")
+}
+
+// startServerHeld starts the server again after stopServerHeld
+func (l *Launcher) startServerHeld() error {
+	l.restartMu.Lock()
+	defer l.restartMu.Unlock()
+
+	l.serverMu.Lock()
+	proc := l.server
+	l.serverMu.Unlock()
+	if proc != nil {
+		select {
+		case <-proc.done:
+		default:
+			return errors.New("Server läuft bereits")
+		}
+	}
+	l.logAndSync("[INFO] Starting Node.js server via control API...")
+	_, err := l.startTool()
+	return err
+}
+
+// handleControl serves POST /api/launcher/{start,stop,restart,profile,config-dir}
+func (l *Launcher) handleControl(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/launcher/config-dir" {
+		// Only answered while the splash page offers the move
+		if err := l.relocateConfig(r); err != nil {
+			writeJSON(w, http.StatusConflict, map[string]interface{}{"success": false, "error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"success": true})
+		return
+	}
+	if r.URL.Path == "/api/launcher/profile" {
+		// Also answered during startup for the profile picker
+		if err := l.switchProfile(r.FormValue("name")); err != nil {
+			writeJSON(w, http.StatusConflict, map[string]interface{}{"success": false, "error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"success": true, "status": l.controlStatus()})
+		return
+	}
+	if !l.ready.Load() {
+		writeJSON(w, http.StatusConflict, map[string]interface{}{"success": false, "error": "Launcher startet noch"})
+		return
+	}
+
+	var err error
+	switch action := strings.TrimPrefix(r.URL.Path, "/api/launcher/"); action {
+	case "start":
+		err = l.startServerHeld()
+	case "stop":
+		l.stopServerHeld()
+	case "restart":
+		if r.URL.Query().Get("defer") != "" {
+			// Scripted/scheduled restarts: wait for the LIVE to end
+			l.requestRestart("Control API", false)
+			break
+		}
+		err = l.restartServer()
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{"success": false, "error": "Unbekannte Aktion: " + action})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusConflict, map[string]interface{}{"success": false, "error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"success": true, "status": l.controlStatus()})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// settingsField is one .env setting as shown on the settings page. Secret
+// values are never sent to the browser, only whether they are set.
+type settingsField struct {
+	dotenv.Field
+	Value string
+	IsSet bool
+}
+
+type settingsSection struct {
+	Title  string
+	Fields []settingsField
+}
+
+type settingsPage struct {
+	Sections []settingsSection
+	Errors   []string
+	Saved    bool
+	Restart  bool
+	Deferred bool // Restart waits until the LIVE has ended
+}
+
+// loadSettingsPage builds the settings form from .env.example and the
+// current .env
+func (l *Launcher) loadSettingsPage() (*settingsPage, *dotenv.File, []dotenv.Field, error) {
+	exampleData, err := os.ReadFile(filepath.Join(l.appDir, ".env.example"))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf(".env.example nicht gefunden")
+	}
+	example := dotenv.Parse(exampleData)
+	env, err := dotenv.ReadFile(filepath.Join(l.appDir, ".env"))
+	if err != nil {
+		// No .env yet - saving creates it from the example, like autoFixEnvFile
+		env = dotenv.Parse(exampleData)
+	}
+
+	fields := dotenv.Fields(example)
+	page := &settingsPage{}
+	for _, f := range fields {
+		value, isSet := env.Get(f.Key)
+		sf := settingsField{Field: f, IsSet: isSet && value != ""}
+		if !f.Secret {
+			sf.Value = value
+		}
+		if n := len(page.Sections); n == 0 || page.Sections[n-1].Title != f.Section {
+			page.Sections = append(page.Sections, settingsSection{Title: f.Section})
+		}
+		last := &page.Sections[len(page.Sections)-1]
+		last.Fields = append(last.Fields, sf)
+	}
+	return page, env, fields, nil
+}
+
+// handleSettings shows and saves the .env editor
+func (l *Launcher) handleSettings(w http.ResponseWriter, r *http.Request) {
+	page, env, fields, err := l.loadSettingsPage()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for _, f := range fields {
+			if _, submitted := r.PostForm[f.Key]; !submitted {
+				continue
+			}
+			value := strings.TrimSpace(r.PostForm.Get(f.Key))
+			current, active := env.Get(f.Key)
+			switch {
+			case f.Secret && r.PostForm.Get("clear_"+f.Key) != "":
+				env.Unset(f.Key)
+			case f.Secret && value == "":
+				// Empty password field keeps the stored secret
+			case value == "" && active:
+				env.Unset(f.Key)
+			case value != "" && value != current:
+				if err := env.Set(f.Key, value); err != nil {
+					page.Errors = append(page.Errors, err.Error())
+				}
+			}
+		}
+
+		for _, p := range dotenv.Validate(env) {
+			page.Errors = append(page.Errors, p.String())
+		}
+		if len(page.Errors) == 0 {
+			if err := dotenv.WriteFile(filepath.Join(l.appDir, ".env"), env); err != nil {
+				page.Errors = append(page.Errors, fmt.Sprintf("Speichern fehlgeschlagen: %v", err))
+			} else {
+				l.logAndSync("[INFO] .env updated from launcher settings page")
+				page, _, _, _ = l.loadSettingsPage()
+				page.Saved = true
+				if r.PostForm.Get("restart") != "" {
+					page.Restart = true
+					page.Deferred = l.requestRestart("Einstellungen geändert", false)
+				}
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := settingsTemplate.Execute(w, page); err != nil {
+		l.logger.Printf("[ERROR] settings template: %v\n", err)
+	}
+}
+
+var settingsTemplate = template.Must(template.New("settings").Parse(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>TikTok Stream Tool - Einstellungen</title>
+    <style>
+        body {
+            margin: 0;
+            padding: 30px;
+            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Arial, sans-serif;
+            min-height: 100vh;
+            box-sizing: border-box;
+        }
+        .settings-container {
+            max-width: 900px;
+            margin: 0 auto;
+            background-color: rgba(255, 255, 255, 0.95);
+            border-radius: 10px;
+            padding: 25px;
+            box-shadow: 0 4px 12px rgba(0, 0, 0, 0.2);
+        }
+        h1 {
+            font-size: 24px;
+            color: #333;
+            margin: 0 0 15px 0;
+            padding-bottom: 10px;
+            border-bottom: 3px solid #667eea;
+        }
+        h2 {
+            color: #764ba2;
+            font-size: 16px;
+            margin: 25px 0 10px 0;
+        }
+        .field {
+            margin-bottom: 14px;
+        }
+        .field label {
+            display: block;
+            font-weight: 600;
+            color: #333;
+            font-size: 13px;
+            font-family: Consolas, monospace;
+        }
+        .field input[type=text], .field input[type=password] {
+            width: 100%;
+            padding: 8px 10px;
+            border: 1px solid #ccc;
+            border-radius: 6px;
+            box-sizing: border-box;
+            font-size: 14px;
+        }
+        .help {
+            color: #777;
+            font-size: 12px;
+            white-space: pre-line;
+            margin: 3px 0 5px 0;
+        }
+        .secret-state {
+            font-size: 12px;
+            color: #555;
+        }
+        .message {
+            padding: 10px 15px;
+            border-radius: 6px;
+            margin-bottom: 15px;
+        }
+        .message.ok { background: #e6f4ea; color: #1e7e34; }
+        .message.error { background: #fdecea; color: #b00020; }
+        .actions {
+            display: flex;
+            gap: 15px;
+            align-items: center;
+            margin-top: 25px;
+        }
+        button {
+            padding: 10px 20px;
+            background: linear-gradient(135deg, #667eea, #764ba2);
+            color: white;
+            border: none;
+            border-radius: 8px;
+            font-weight: 600;
+            font-size: 14px;
+            cursor: pointer;
+        }
+    </style>
+</head>
+<body>
+    <div class="settings-container">
+        <h1>⚙️ Einstellungen (app/.env)</h1>
+        {{if .Saved}}<div class="message ok">✅ Gespeichert.{{if .Deferred}} Du bist gerade live - der Server wird nach dem Stream automatisch neu gestartet.{{else if .Restart}} Server wird neu gestartet...{{else}} Änderungen gelten nach dem nächsten Server-Neustart.{{end}}</div>{{end}}
+        {{range .Errors}}<div class="message error">❌ {{.}}</div>{{end}}
+        <form method="POST" action="/settings" autocomplete="off">
+            {{range .Sections}}
+            <h2>{{.Title}}</h2>
+            {{range .Fields}}
+            <div class="field">
+                <label for="{{.Key}}">{{.Key}}</label>
+                {{if .Help}}<div class="help">{{.Help}}</div>{{end}}
+                {{if .Secret}}
+                <input type="password" id="{{.Key}}" name="{{.Key}}" value="" placeholder="{{if .IsSet}}•••••••• (gesetzt - leer lassen zum Beibehalten){{else}}(nicht gesetzt){{end}}">
+                {{if .IsSet}}<label class="secret-state"><input type="checkbox" name="clear_{{.Key}}" value="1"> Entfernen</label>{{end}}
+                {{else}}
+                <input type="text" id="{{.Key}}" name="{{.Key}}" value="{{.Value}}" placeholder="{{.Default}}">
+                {{end}}
+            </div>
+            {{end}}
+            {{end}}
+            <div class="actions">
+                <button type="submit">💾 Speichern</button>
+                <label><input type="checkbox" name="restart" value="1"> Server sofort neu starten</label>
+            </div>
+        </form>
+    </div>
+</body>
+</html>
+`))
+
+// setPluginEnabled switches a plugin. A running server loads or unloads it
+// right away (and keeps its state file consistent); otherwise
+// plugins_state.json is updated for the next start.
+func (l *Launcher) setPluginEnabled(id string, on bool) error {
+	dir := filepath.Join(l.appDir, "plugins")
+	p, err := plugins.Find(dir, id)
+	if err != nil {
+		return err
+	}
+	if on {
+		if err := p.CanEnable(); err != nil {
+			return err
+		}
+	}
+
+	l.restartMu.Lock()
+	defer l.restartMu.Unlock()
+
+	l.serverMu.Lock()
+	proc := l.server
+	l.serverMu.Unlock()
+	running := false
+	if proc != nil {
+		select {
+		case <-proc.done:
+		default:
+			running = true
+		}
+	}
+	if !running {
+		return plugins.SetEnabled(dir, id, on)
+	}
+	if !l.ready.Load() {
+		return errors.New("Server startet noch - bitte gleich noch einmal versuchen")
+	}
+	return serverapi.SetPluginEnabled(l.port, id, on)
+}
+
+type pluginsPage struct {
+	Plugins []plugins.Plugin
+	Message string
+	Error   string
+}
+
+// handlePlugins lists the plugins and switches them on or off
+func (l *Launcher) handlePlugins(w http.ResponseWriter, r *http.Request) {
+	page := &pluginsPage{}
+	if r.Method == http.MethodPost {
+		id := r.FormValue("id")
+		on := r.FormValue("action") == "enable"
+		if err := l.setPluginEnabled(id, on); err != nil {
+			page.Error = err.Error()
+		} else if on {
+			l.logAndSync("[INFO] Plugin %s enabled from launcher plugins page", id)
+			page.Message = fmt.Sprintf("Plugin %s aktiviert", id)
+		} else {
+			l.logAndSync("[INFO] Plugin %s disabled from launcher plugins page", id)
+			page.Message = fmt.Sprintf("Plugin %s deaktiviert", id)
+		}
+	}
+
+	list, err := plugins.List(filepath.Join(l.appDir, "plugins"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	page.Plugins = list
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := pluginsTemplate.Execute(w, page); err != nil {
+		l.logger.Printf("[ERROR] plugins template: %v\n", err)
+	}
+}
+
+var pluginsTemplate = template.Must(template.New("plugins").Parse(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>TikTok Stream Tool - Plugins</title>
+    <style>
+        body {
+            margin: 0;
+            padding: 30px;
+            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Arial, sans-serif;
+            min-height: 100vh;
+            box-sizing: border-box;
+        }
+        .plugins-container {
+            max-width: 900px;
+            margin: 0 auto;
+            background-color: rgba(255, 255, 255, 0.95);
+            border-radius: 10px;
+            padding: 25px;
+            box-shadow: 0 4px 12px rgba(0, 0, 0, 0.2);
+        }
+        h1 {
+            font-size: 24px;
+            color: #333;
+            margin: 0 0 15px 0;
+            padding-bottom: 10px;
+            border-bottom: 3px solid #667eea;
+        }
+        .plugin {
+            display: flex;
+            justify-content: space-between;
+            align-items: flex-start;
+            gap: 20px;
+            padding: 14px 0;
+            border-bottom: 1px solid #e0e0e0;
+        }
+        .plugin-name {
+            font-weight: bold;
+            color: #333;
+        }
+        .plugin-meta {
+            color: #999;
+            font-size: 12px;
+            font-family: Consolas, monospace;
+        }
+        .plugin-description {
+            color: #555;
+            font-size: 13px;
+            margin: 4px 0 6px 0;
+        }
+        .permission {
+            display: inline-block;
+            padding: 2px 8px;
+            margin: 2px 4px 2px 0;
+            background: #eef0fb;
+            color: #667eea;
+            border-radius: 10px;
+            font-size: 11px;
+        }
+        .badge {
+            font-size: 12px;
+            font-weight: 600;
+        }
+        .badge.on { color: #1e7e34; }
+        .badge.off { color: #999; }
+        .problem {
+            color: #8a6d00;
+            font-size: 12px;
+            margin-bottom: 4px;
+        }
+        .problem.error { color: #b00020; }
+        .message {
+            padding: 10px 15px;
+            border-radius: 6px;
+            margin-bottom: 15px;
+        }
+        .message.ok { background: #e6f4ea; color: #1e7e34; }
+        .message.error { background: #fdecea; color: #b00020; }
+        button {
+            padding: 8px 16px;
+            background: linear-gradient(135deg, #667eea, #764ba2);
+            color: white;
+            border: none;
+            border-radius: 8px;
+            font-weight: 600;
+            font-size: 13px;
+            cursor: pointer;
+            white-space: nowrap;
+        }
+        button.off {
+            background: #999;
+        }
+    </style>
+</head>
+<body>
+    <div class="plugins-container">
+        <h1>🧩 Plugins (app/plugins)</h1>
+        {{if .Message}}<div class="message ok">✅ {{.Message}}</div>{{end}}
+        {{if .Error}}<div class="message error">❌ {{.Error}}</div>{{end}}
+        {{range .Plugins}}
+        <div class="plugin">
+            <div>
+                <span class="plugin-name">{{if .Name}}{{.Name}}{{else}}{{.ID}}{{end}}</span>
+                <span class="plugin-meta">{{.ID}} {{.Version}}{{if .Type}} · {{.Type}}{{end}}</span>
+                {{if .Disabled}}<span class="badge off">dauerhaft deaktiviert</span>
+                {{else if .Enabled}}<span class="badge on">● aktiv</span>
+                {{else}}<span class="badge off">○ deaktiviert</span>{{end}}
+                <div class="plugin-description">{{.Description}}</div>
+                {{range .Problems}}<div class="problem{{if .Critical}} error{{end}}">{{if .Critical}}❌{{else}}⚠️{{end}} {{.Message}}</div>{{end}}
+                {{range .Permissions}}<span class="permission">{{.}}</span>{{end}}
+            </div>
+            {{if not .Disabled}}
+            <form method="POST" action="/plugins">
+                <input type="hidden" name="id" value="{{.ID}}">
+                {{if .Enabled}}
+                <button type="submit" name="action" value="disable" class="off">Deaktivieren</button>
+                {{else}}
+                <button type="submit" name="action" value="enable">Aktivieren</button>
+                {{end}}
+            </form>
+            {{end}}
+        </div>
+        {{end}}
+    </div>
+</body>
+</html>
+`))
 
-	// Phase 3: Check and install dependencies (30-80%)
-	l.updateProgress(30, "Prüfe Abhängigkeiten...")
-	l.logger.Println("[Phase 3] Checking dependencies...")
-	time.Sleep(300 * time.Millisecond)
+// applyLauncherUpdate installs a launcher build downloaded during an
+// earlier run and restarts into it. A build that fails its selftest is
+// rolled back and this one keeps running.
+func (l *Launcher) applyLauncherUpdate(exePath string) {
+	selfupdate.Cleanup(exePath)
+	version, err := selfupdate.ApplyPending(exePath)
+	if err != nil {
+		l.logAndSync("[ERROR] Launcher update failed, keeping version %s: %v", launcherVersion, err)
+		return
+	}
+	if version == "" {
+		return
+	}
+	l.logAndSync("[SUCCESS] Launcher updated from %s to %s - restarting", launcherVersion, version)
+	if l.lock != nil {
+		l.lock.Release()
+	}
+	if err := selfupdate.Restart(exePath); err != nil {
+		l.logAndSync("[ERROR] Could not start updated launcher: %v", err)
+		os.Exit(exitFailure)
+	}
+}
 
-	if !l.checkNodeModules() {
-		l.updateProgress(40, "Installiere Abhängigkeiten...")
-		l.logger.Println("[INFO] node_modules not found, installing dependencies...")
-		time.Sleep(500 * time.Millisecond)
-		l.updateProgress(45, "HINWEIS: npm install kann einige Minuten dauern, bitte das Fenster offen halten und warten")
+// checkLauncherUpdate downloads a newer launcher build in the background;
+// it is installed on the next start
+func (l *Launcher) checkLauncherUpdate() {
+	if !l.cfg.SelfUpdate || updatePublicKey == "" || launcherVersion == "dev" {
+		return
+	}
+	key, err := selfupdate.ParsePublicKey(updatePublicKey)
+	if err != nil {
+		l.logger.Printf("[WARNING] Launcher update check disabled: %v\n", err)
+		return
+	}
+	exePath, err := os.Executable()
+	if err != nil {
+		return
+	}
+
+	release, err := selfupdate.Check(exePath, l.cfg.UpdateURL, key, launcherVersion)
+	if err != nil {
+		l.logger.Printf("[WARNING] Launcher update check failed: %v\n", err)
+		return
+	}
+	if release == nil {
+		l.logger.Printf("[INFO] Launcher %s is up to date\n", launcherVersion)
+		return
+	}
+	l.logger.Printf("[INFO] Downloading launcher %s...\n", release.Version)
+	if err := selfupdate.Download(exePath, release); err != nil {
+		l.logger.Printf("[WARNING] Launcher update download failed: %v\n", err)
+		return
+	}
+	l.logAndSync("[INFO] Launcher %s downloaded, it is installed on the next start", release.Version)
+}
+
+// changelogView is the JSON answer of GET /api/launcher/changelog and the
+// data of the splash page's changelog panel
+type changelogView struct {
+	Current  string              `json:"current"` // app/package.json version
+	Last     string              `json:"last"`    // version of the previous start, empty on the first
+	Sections []changelog.Section `json:"sections"`
+}
+
+// New returns the sections released since the last start.
+func (v *changelogView) New() []changelog.Section {
+	var list []changelog.Section
+	for _, s := range v.Sections {
+		if s.New {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// loadChangelog parses CHANGELOG.md and marks what is new since the last start
+func (l *Launcher) loadChangelog() (*changelogView, error) {
+	content, err := os.ReadFile(filepath.Join(l.exeDir, "CHANGELOG.md"))
+	if err != nil {
+		return nil, err
+	}
+	sections, err := changelog.Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	changelog.MarkNew(sections, l.lastVersion, l.appVersion)
+	return &changelogView{Current: l.appVersion, Last: l.lastVersion, Sections: sections}, nil
+}
+
+// handleChangelog serves the changelog panel: new versions highlighted on
+// top, the full history collapsed below
+func (l *Launcher) handleChangelog(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	view, err := l.loadChangelog()
+	if err != nil {
+		w.Write([]byte("<p style='color: #999;'>Changelog konnte nicht geladen werden.</p>"))
+		return
+	}
+	if err := changelogTemplate.Execute(w, view); err != nil {
+		l.logger.Printf("[ERROR] changelog template: %v\n", err)
+	}
+}
+
+var changelogTemplate = template.Must(template.New("changelog").Funcs(template.FuncMap{
+	"safe": func(s string) template.HTML { return template.HTML(s) }, // sanitized by the changelog package
+}).Parse(`{{with .New}}<div class="changelog-new">
+    <div class="changelog-new-title">✨ Neu {{if $.Last}}seit Version {{$.Last}}{{else}}in Version {{$.Current}}{{end}}</div>
+    {{range .}}<h2>{{.Version}}{{if .Title}} - {{.Title}}{{end}}</h2>
+    {{safe .HTML}}{{end}}
+</div>
+{{else}}{{if .Last}}<p class="changelog-none">Keine Änderungen seit dem letzten Start (Version {{.Last}}).</p>{{end}}{{end}}
+<details class="changelog-history"{{if not .New}} open{{end}}>
+    <summary>Alle Versionen</summary>
+    {{range .Sections}}<h2>{{.Version}}{{if .Title}} - {{.Title}}{{end}}</h2>
+    {{safe .HTML}}{{end}}
+</details>
+`))
+
+// runServiceCommand implements "launcher service install|uninstall|status".
+// Arguments after "--" are passed on to the headless launcher.
+func runServiceCommand(exePath string, args []string) int {
+	const usage = "Verwendung: launcher service install|uninstall|status [--name NAME] [--unit-dir DIR] [--dry-run] [-- Launcher-Optionen]"
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return exitConfig
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("service "+action, flag.ContinueOnError)
+	name := fs.String("name", service.DefaultName, "Name des Dienstes")
+	dir := fs.String("unit-dir", "", "Verzeichnis für die Unit-/Agent-Datei (Standard: Benutzerverzeichnis des Dienst-Managers)")
+	dryRun := fs.Bool("dry-run", false, "Nur die Datei schreiben, den Dienst-Manager nicht aufrufen")
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitConfig
+	}
+	opts := service.Options{Dir: *dir, DryRun: *dryRun}
+
+	// The remaining arguments are launcher flags; check them now instead of
+	// letting the service fail on every start
+	exeDir := filepath.Dir(exePath)
+	cfg, err := config.Load(exeDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "[ERROR] Ungültige Launcher-Konfiguration:", err)
+		return exitConfig
+	}
+	launcherFlags := flag.NewFlagSet("launcher", flag.ContinueOnError)
+	cfg.RegisterFlags(launcherFlags)
+	if err := launcherFlags.Parse(fs.Args()); err != nil {
+		return exitConfig
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, "[ERROR]", err)
+		return exitConfig
+	}
 
-		err = l.installDependencies()
+	switch action {
+	case "install":
+		if resolved, err := filepath.EvalSymlinks(exePath); err == nil {
+			exePath = resolved
+		}
+		spec := service.Spec{
+			Name:        *name,
+			Description: "LTTH - PupCid's Little TikTok Helper",
+			Exe:         exePath,
+			Args:        append([]string{"--headless"}, fs.Args()...),
+			WorkDir:     exeDir,
+			StopTimeout: cfg.ShutdownTimeout.D() + 5*time.Second,
+		}
+		if runtime.GOOS != "windows" {
+			// Service managers start with a minimal PATH; keep the one node
+			// and npm were found in
+			spec.Env = map[string]string{"PATH": os.Getenv("PATH")}
+		}
+		path, err := service.Install(spec, opts)
 		if err != nil {
-			l.logger.Printf("[ERROR] Dependency installation failed: %v\n", err)
-			l.updateProgress(45, fmt.Sprintf("FEHLER: %v", err))
-			time.Sleep(5 * time.Second)
-			l.closeLogging()
-			os.Exit(1)
+			fmt.Fprintln(os.Stderr, "[ERROR] Dienst-Installation fehlgeschlagen:", err)
+			return exitFailure
+		}
+		fmt.Printf("[SUCCESS] Dienst %s installiert: %s\n", *name, path)
+		if opts.DryRun {
+			fmt.Println("[INFO] --dry-run: Dienst wurde nicht aktiviert")
+		} else if runtime.GOOS == "linux" {
+			fmt.Println("[INFO] Start ohne Anmeldung: loginctl enable-linger $USER")
+			fmt.Printf("[INFO] Log anzeigen: journalctl --user -u %s -f\n", *name)
 		}
+		return exitOK
 
-		l.updateProgress(80, "Installation abgeschlossen!")
-		l.logger.Println("[SUCCESS] Dependencies installed successfully")
-	} else {
-		l.updateProgress(80, "Abhängigkeiten bereits installiert...")
-		l.logger.Println("[INFO] Dependencies already installed")
+	case "uninstall":
+		if err := service.Uninstall(*name, opts); err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR] Dienst konnte nicht entfernt werden:", err)
+			return exitFailure
+		}
+		fmt.Printf("[SUCCESS] Dienst %s entfernt\n", *name)
+		return exitOK
+
+	case "status":
+		st, err := service.Query(*name, opts)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR]", err)
+			return exitFailure
+		}
+		yesNo := map[bool]string{true: "ja", false: "nein"}
+		fmt.Printf("Dienst:       %s\n", *name)
+		fmt.Printf("Installiert:  %s (%s)\n", yesNo[st.Installed], st.Path)
+		fmt.Printf("Autostart:    %s\n", yesNo[st.Enabled])
+		if st.Detail != "" {
+			fmt.Printf("Status:       %s\n", st.Detail)
+		}
+		// The lock file tells whether a launcher runs, however it was started
+		running := "nein"
+		if info, err := instancelock.Read(cfg.ResolveAppDir(exeDir)); err == nil && procutil.Alive(info.PID) {
+			running = fmt.Sprintf("ja (PID %d)", info.PID)
+		}
+		fmt.Printf("Launcher:     %s\n", running)
+		return exitOK
 	}
-	time.Sleep(300 * time.Millisecond)
 
-	// Phase 3.5: Auto-fix common issues (80-89%)
-	l.updateProgress(82, "Prüfe Konfiguration...")
-	l.logger.Println("[Phase 3.5] Auto-fixing common issues...")
-	time.Sleep(300 * time.Millisecond)
-	
-	// Auto-fix: Create .env file if missing
-	if err := l.autoFixEnvFile(); err != nil {
-		l.logger.Printf("[WARNING] Could not auto-create .env: %v\n", err)
+	fmt.Fprintln(os.Stderr, usage)
+	return exitConfig
+}
+
+const forceUsage = "Auch ohne Launcher-Sperre ausführen (nur wenn sicher kein Launcher läuft)"
+
+// commandLockFailed handles an instance lock that could not be taken for
+// another reason than a running launcher, e.g. a read-only app directory.
+// Whether a launcher runs is unknown then, so commands that change its
+// files only continue with --force.
+func commandLockFailed(err error, force bool) bool {
+	if err == nil {
+		return false
 	}
-	
-	// Auto-fix: Check port availability
-	l.autoFixPort()
-	
-	l.updateProgress(89, "Konfiguration geprüft!")
-	time.Sleep(300 * time.Millisecond)
+	if !force {
+		fmt.Fprintf(os.Stderr, "[ERROR] Launcher-Sperre nicht verfügbar: %v - mit --force ausführen, wenn sicher kein Launcher läuft\n", err)
+		return true
+	}
+	fmt.Fprintf(os.Stderr, "[WARNING] Launcher-Sperre nicht verfügbar, fahre wegen --force fort: %v\n", err)
+	return false
+}
 
-	// Phase 4: Start tool (90-100%)
-	l.updateProgress(90, "Starte Tool...")
-	l.logger.Println("[Phase 4] Starting Node.js server...")
-	time.Sleep(500 * time.Millisecond)
+// runRestoreCommand implements "launcher restore [--list] [NUMMER|DATEI]".
+// Without an argument the snapshots are listed and one can be picked.
+func runRestoreCommand(exeDir string, args []string) int {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	list := fs.Bool("list", false, "Nur die vorhandenen Backups anzeigen")
+	force := fs.Bool("force", false, forceUsage)
+	cfg, cfgErr := config.Load(exeDir)
+	cfg.RegisterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitConfig
+	}
+	if cfgErr == nil {
+		cfgErr = cfg.Validate()
+	}
+	if cfgErr != nil {
+		fmt.Fprintln(os.Stderr, "[ERROR] Ungültige Launcher-Konfiguration:", cfgErr)
+		return exitConfig
+	}
 
-	// Start the tool
-	cmd, err := l.startTool()
+	dir := cfg.ResolveBackupDir(exeDir)
+	appDir := cfg.ResolveAppDir(exeDir)
+	snapshots, err := backup.List(dir)
 	if err != nil {
-		l.logger.Printf("[ERROR] Failed to start server: %v\n", err)
-		l.updateProgress(90, fmt.Sprintf("FEHLER beim Starten: %v", err))
-		l.updateProgress(90, "Prüfe bitte die Log-Datei in app/logs/ für Details.")
-		time.Sleep(30 * time.Second)
-		l.closeLogging()
-		os.Exit(1)
+		fmt.Fprintln(os.Stderr, "[ERROR]", err)
+		return exitFailure
+	}
+	if len(snapshots) == 0 {
+		fmt.Printf("Keine Backups in %s gefunden\n", dir)
+		return exitOK
 	}
 
-	// Monitor if the process exits prematurely
-	processDied := make(chan error, 1)
-	go func() {
-		processDied <- cmd.Wait()
-	}()
+	choice := fs.Arg(0)
+	if *list || choice == "" {
+		fmt.Printf("Backups in %s:\n", dir)
+		for i, s := range snapshots {
+			fmt.Printf("  %2d) %s  %-12s %4d Dateien  %s\n", i+1, s.Created.Local().Format("2006-01-02 15:04:05"), s.Reason, s.Files, formatBytes(s.Bytes))
+		}
+		if *list {
+			return exitOK
+		}
+		fmt.Print("Nummer des Backups zum Wiederherstellen (Enter = abbrechen): ")
+		fmt.Scanln(&choice)
+		if choice == "" {
+			return exitOK
+		}
+	}
 
-	// Wait for server to be ready
-	l.updateProgress(93, "Warte auf Server-Start...")
-	l.logger.Println("[INFO] Waiting for server health check (60s timeout)...")
-	l.logger.Println("[INFO] Checking if server responds on http://localhost:3000...")
+	var snap *backup.Info
+	if n, err := strconv.Atoi(choice); err == nil && n >= 1 && n <= len(snapshots) {
+		snap = &snapshots[n-1]
+	} else {
+		for i := range snapshots {
+			if snapshots[i].Path == choice || filepath.Base(snapshots[i].Path) == choice {
+				snap = &snapshots[i]
+			}
+		}
+	}
+	if snap == nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] Backup %q nicht gefunden\n", choice)
+		return exitConfig
+	}
 
-	// Check server health with process monitoring
-	healthCheckTimeout := time.After(60 * time.Second)
-	healthCheckTicker := time.NewTicker(1 * time.Second)
-	defer healthCheckTicker.Stop()
+	// Files must not change under a running server
+	lock, err := instancelock.Acquire(appDir, "")
+	var held *instancelock.HeldError
+	if errors.As(err, &held) {
+		fmt.Fprintf(os.Stderr, "[ERROR] Launcher läuft (PID %d) - bitte zuerst beenden\n", held.Info.PID)
+		return exitAlreadyRunning
+	}
+	if commandLockFailed(err, *force) {
+		return exitFailure
+	}
+	defer lock.Release()
 
-	serverReady := false
-	attemptCount := 0
-	lastLogTime := time.Now()
-	
-	for !serverReady {
-		select {
-		case err := <-processDied:
-			// Process exited before server was ready
-			// Ensure log file is flushed to capture all server output
-			if l.logFile != nil {
-				l.logFile.Sync()
-				time.Sleep(100 * time.Millisecond) // Give a moment for any buffered writes
+	// --config-dir pins the directory, otherwise it follows the restored .config_path
+	configDir := cfg.ResolveConfigDir(exeDir)
+	src := backup.Sources{AppDir: appDir, ConfigDir: configDir}
+	if src.ConfigDir == "" {
+		src.ConfigDir = backup.ConfigDir(appDir)
+	}
+	if path, err := backup.Create(dir, src, "pre-restore", time.Now()); err != nil {
+		fmt.Fprintln(os.Stderr, "[ERROR] Aktueller Stand konnte nicht gesichert werden:", err)
+		return exitFailure
+	} else if path != "" {
+		fmt.Printf("[INFO] Aktueller Stand gesichert: %s\n", path)
+	}
+	if err := backup.Restore(snap.Path, backup.Sources{AppDir: appDir, ConfigDir: configDir}); err != nil {
+		fmt.Fprintln(os.Stderr, "[ERROR] Wiederherstellung fehlgeschlagen:", err)
+		return exitFailure
+	}
+	fmt.Printf("[SUCCESS] Backup vom %s wiederhergestellt\n", snap.Created.Local().Format("2006-01-02 15:04:05"))
+	return exitOK
+}
+
+// runPluginsCommand handles "launcher plugins list|enable|disable ID...",
+// "install ZIP|URL..." and "update|remove ID...". Changes only work while
+// no launcher runs; a running server is switched on the launcher's
+// /plugins page instead.
+func runPluginsCommand(exeDir string, args []string) int {
+	usage := "Verwendung: launcher plugins list|enable|disable|install|update|remove [--app-dir DIR] [--force] [ID|ZIP|URL...]"
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return exitConfig
+	}
+	action := args[0]
+	fs := flag.NewFlagSet("plugins", flag.ContinueOnError)
+	force := fs.Bool("force", false, forceUsage)
+	cfg, cfgErr := config.Load(exeDir)
+	cfg.RegisterFlags(fs)
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitConfig
+	}
+	if cfgErr == nil {
+		cfgErr = cfg.Validate()
+	}
+	if cfgErr != nil {
+		fmt.Fprintln(os.Stderr, "[ERROR] Ungültige Launcher-Konfiguration:", cfgErr)
+		return exitConfig
+	}
+	appDir := cfg.ResolveAppDir(exeDir)
+	dir := filepath.Join(appDir, "plugins")
+
+	switch action {
+	case "list":
+		list, err := plugins.List(dir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR]", err)
+			return exitFailure
+		}
+		for _, p := range list {
+			status := "deaktiviert"
+			switch {
+			case p.Disabled:
+				status = "gesperrt"
+			case p.Enabled:
+				status = "aktiv"
 			}
-			
-			l.logAndSync("--- Node.js Server Output End ---")
-			l.logAndSync("[ERROR] ===========================================")
-			l.logAndSync("[ERROR] Node.js process exited prematurely: %v", err)
-			l.logAndSync("[ERROR] Server crashed during startup!")
-			l.logAndSync("[ERROR] Check the server output above for the actual error")
-			l.logAndSync("[ERROR] ===========================================")
-			l.logAndSync("[ERROR] Häufige Ursachen:")
-			l.logAndSync("[ERROR]  - Fehlende .env Datei (kopiere .env.example zu .env)")
-			l.logAndSync("[ERROR]  - Port 3000 bereits belegt")
-			l.logAndSync("[ERROR]  - Fehlende Dependencies (führe 'npm install' aus)")
-			l.logAndSync("[ERROR]  - Syntax-Fehler im Code")
-			l.logAndSync("[ERROR] ===========================================")
-			
-			// Check if we just fixed the .env file - if so, retry once
-			if l.envFileFixed {
-				l.logAndSync("[AUTO-FIX] .env file was just created - attempting restart...")
-				l.updateProgress(95, "🔄 .env erstellt - starte Server neu...")
-				time.Sleep(3 * time.Second)
-				
-				// Mark that we already tried the fix
-				l.envFileFixed = false
-				
-				// Start server again
-				cmd, err = l.startTool()
-				if err != nil {
-					l.logAndSync("[ERROR] Retry failed to start server: %v", err)
-				} else {
-					// Monitor the restarted process
-					go func() {
-						processDied <- cmd.Wait()
-					}()
-					
-					l.updateProgress(96, "🔄 Server neugestartet - warte auf Antwort...")
-					l.logAndSync("[INFO] Server restarted after .env fix - waiting for health check...")
-					
-					// Reset the ticker for another try
-					continue
+			fmt.Printf("%-22s %-9s %-12s %s\n", p.ID, p.Version, status, strings.Join(p.Permissions, ", "))
+			for _, prob := range p.Problems {
+				level := "[WARNING]"
+				if prob.Critical {
+					level = "[ERROR]"
 				}
+				fmt.Printf("%-22s %s %s\n", "", level, prob)
 			}
-			
-			l.updateProgress(95, "⚠️ Server konnte nicht starten!")
-			time.Sleep(2 * time.Second)
-			l.updateProgress(96, "📋 Alle Auto-Fixes wurden versucht")
-			time.Sleep(2 * time.Second)
-			l.updateProgress(97, "💡 Prüfe app/logs/launcher_*.log für Details")
-			time.Sleep(2 * time.Second)
-			l.updateProgress(98, "💡 Oder führe manuell: cd app && npm install")
-			time.Sleep(2 * time.Second)
-			l.updateProgress(99, "💡 Oder prüfe ob Port 3000 frei ist")
-			time.Sleep(2 * time.Second)
-			l.updateProgress(100, "❌ Launcher wird in 15 Sekunden geschlossen...")
-			time.Sleep(15 * time.Second)
-			l.closeLogging()
-			os.Exit(1)
-		case <-healthCheckTicker.C:
-			attemptCount++
-			
-			// Log progress every 5 seconds
-			if time.Since(lastLogTime) >= 5 * time.Second {
-				l.logger.Printf("[INFO] Health check attempt %d (waiting for server to respond)...\n", attemptCount)
-				l.updateProgress(93 + (attemptCount / 5), fmt.Sprintf("Warte auf Server... (Versuch %d)", attemptCount))
-				lastLogTime = time.Now()
-			}
-			
-			// Try multiple ports (server might have failed over)
-			ports := []int{3000, 3001, 3002, 3003, 3004}
-			for _, port := range ports {
-				if l.checkServerHealthOnPort(port) {
-					l.logger.Printf("[SUCCESS] Server responded on port %d!\n", port)
-					if port != 3000 {
-						l.logger.Printf("[INFO] Note: Server is running on port %d instead of 3000\n", port)
-					}
-					serverReady = true
-					break
-				}
+		}
+		return exitOK
+	case "enable", "disable", "install", "update", "remove":
+		if fs.NArg() == 0 {
+			fmt.Fprintln(os.Stderr, usage)
+			return exitConfig
+		}
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		return exitConfig
+	}
+
+	// The server keeps the plugin state in memory and would overwrite the file
+	lock, err := instancelock.Acquire(appDir, "")
+	var held *instancelock.HeldError
+	if errors.As(err, &held) {
+		fmt.Fprintf(os.Stderr, "[ERROR] Launcher läuft (PID %d) - Plugins bitte auf der Launcher-Startseite unter \"Plugins\" umschalten\n", held.Info.PID)
+		return exitAlreadyRunning
+	}
+	if commandLockFailed(err, *force) {
+		return exitFailure
+	}
+	defer lock.Release()
+
+	switch action {
+	case "install", "update":
+		return installPlugins(dir, action == "update", fs.Args(), pluginNPM(appDir, npmArgs(cfg, exeDir)))
+	case "remove":
+		code := exitOK
+		for _, id := range fs.Args() {
+			if err := plugins.Remove(dir, id); err != nil {
+				fmt.Fprintln(os.Stderr, "[ERROR]", err)
+				code = exitFailure
+				continue
 			}
-		case <-healthCheckTimeout:
-			l.logger.Println("[ERROR] Server health check timed out after 60 seconds")
-			l.logger.Println("[ERROR] Server did not respond. Check the log above for error messages.")
-			l.logger.Println("[ERROR] ===========================================")
-			l.logger.Println("[ERROR] Mögliche Probleme:")
-			l.logger.Println("[ERROR]  - Server startet, aber hängt sich bei Initialisierung auf")
-			l.logger.Println("[ERROR]  - Dependencies werden geladen (kann lange dauern)")
-			l.logger.Println("[ERROR]  - Datenbank-Migration läuft")
-			l.logger.Println("[ERROR]  - Port 3000 ist blockiert durch Firewall")
-			l.logger.Println("[ERROR] ===========================================")
-			
-			l.updateProgress(95, "⏱️ Server-Start Timeout (60s)")
-			time.Sleep(2 * time.Second)
-			l.updateProgress(96, "📋 Server antwortet nicht - prüfe app/logs/")
-			time.Sleep(2 * time.Second)
-			l.updateProgress(97, "💡 Server läuft evtl. noch im Hintergrund")
-			time.Sleep(2 * time.Second)
-			l.updateProgress(98, "💡 Warte 2-3 Minuten und öffne localhost:3000")
-			time.Sleep(2 * time.Second)
-			l.updateProgress(100, "❌ Launcher wird in 15 Sekunden geschlossen...")
-			time.Sleep(15 * time.Second)
-			l.closeLogging()
-			os.Exit(1)
+			fmt.Printf("[SUCCESS] Plugin %s entfernt\n", id)
+		}
+		return code
+	}
+
+	code := exitOK
+	for _, id := range fs.Args() {
+		if err := plugins.SetEnabled(dir, id, action == "enable"); err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR]", err)
+			code = exitFailure
+			continue
+		}
+		if action == "enable" {
+			fmt.Printf("[SUCCESS] Plugin %s aktiviert\n", id)
+		} else {
+			fmt.Printf("[SUCCESS] Plugin %s deaktiviert\n", id)
+		}
+	}
+	return code
+}
+
+// runDoctorCommand handles "launcher doctor": it runs the launcher's
+// startup checks without fixing or starting anything and prints every
+// finding. The exit code is 1 if the server would not start cleanly.
+func runDoctorCommand(exeDir string, args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	cfg, cfgErr := config.Load(exeDir)
+	cfg.RegisterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitConfig
+	}
+	if cfgErr == nil {
+		cfgErr = cfg.Validate()
+	}
+
+	errorCount := 0
+	report := func(level, format string, a ...interface{}) {
+		if level == "[ERROR]" {
+			errorCount++
 		}
+		fmt.Printf("%-10s %s\n", level, fmt.Sprintf(format, a...))
+	}
+
+	if cfgErr != nil {
+		report("[ERROR]", "Launcher-Konfiguration: %v", cfgErr)
+		return exitConfig
 	}
+	report("[OK]", "Launcher-Konfiguration")
 
-	l.updateProgress(100, "Server erfolgreich gestartet!")
-	l.logger.Println("[SUCCESS] Server is running and healthy!")
-	time.Sleep(500 * time.Millisecond)
-	l.updateProgress(100, "Weiterleitung zum Dashboard...")
-	l.logger.Println("[INFO] Redirecting to dashboard...")
-	time.Sleep(500 * time.Millisecond)
-	l.sendRedirect()
+	l := NewLauncher()
+	l.cfg = cfg
+	l.exeDir = exeDir
+	l.appDir = cfg.ResolveAppDir(exeDir)
+	if info, err := os.Stat(l.appDir); err != nil || !info.IsDir() {
+		report("[ERROR]", "App-Verzeichnis %s nicht gefunden", l.appDir)
+		return exitFailure
+	}
+	report("[OK]", "App-Verzeichnis %s", l.appDir)
 
-	// Keep server running to allow redirect to complete
-	time.Sleep(3 * time.Second)
-	l.closeLogging()
-	os.Exit(0)
-}
+	problems := preflight.Check(l.appDir, l.configDir(), !l.checkNodeModules())
+	for _, p := range problems {
+		if p.Critical {
+			report("[ERROR]", "%s", p)
+		} else {
+			report("[WARNING]", "%s", p)
+		}
+	}
+	if len(problems) == 0 {
+		free, _ := preflight.FreeSpace(l.appDir)
+		report("[OK]", "Schreibzugriff und Speicherplatz (%s frei)", formatBytes(int64(free)))
+	}
 
-// parseChangelogToHTML converts markdown changelog to HTML
-func parseChangelogToHTML(markdown string) string {
-	lines := strings.Split(markdown, "\n")
-	var html strings.Builder
-	inList := false
-	
-	// Only show the first 50 lines (recent changes)
-	maxLines := 50
-	if len(lines) > maxLines {
-		lines = lines[:maxLines]
+	nodeOK := false
+	if err := l.checkNodeJS(); err != nil {
+		report("[ERROR]", "%v", err)
+	} else {
+		nodeOK = true
+		report("[OK]", "Node.js %s (%s)", strings.TrimSpace(l.getNodeVersion()), l.nodePath)
 	}
-	
-	for _, line := range lines {
-		line = strings.TrimRight(line, "\r")
-		
-		// Skip the title and format line
-		if strings.HasPrefix(line, "# Changelog") {
-			continue
+
+	if !l.checkNodeModules() {
+		report("[ERROR]", "node_modules fehlt (npm install ausführen)")
+	} else {
+		report("[OK]", "node_modules vorhanden")
+		if nodeOK {
+			if result, err := nodeabi.Check(l.nodePath, l.appDir); err != nil {
+				report("[WARNING]", "ABI-Prüfung übersprungen: %v", err)
+			} else if result.Mismatch {
+				report("[ERROR]", "Native Module passen nicht zu Node.js: %s (npm rebuild better-sqlite3)", result.Reason)
+			} else {
+				report("[OK]", "Native Module (ABI %s)", result.RuntimeABI)
+			}
 		}
-		if strings.HasPrefix(line, "All notable changes") {
-			continue
+		if stale, err := npmdeps.Check(l.appDir); err != nil {
+			report("[WARNING]", "package.json nicht lesbar: %v", err)
+		} else if len(stale) > 0 {
+			for _, s := range stale {
+				report("[WARNING]", "Abhängigkeit %s (wird beim Start installiert)", s)
+			}
+		} else {
+			report("[OK]", "Abhängigkeiten passen zu package.json")
 		}
-		if strings.HasPrefix(line, "The format is") {
+	}
+
+	if env, err := dotenv.ReadFile(filepath.Join(l.appDir, ".env")); err != nil {
+		report("[WARNING]", ".env fehlt (wird beim Start aus .env.example angelegt)")
+	} else {
+		problems := dotenv.Validate(env)
+		for _, p := range problems {
+			if p.Critical {
+				report("[ERROR]", ".env: %s", p)
+			} else {
+				report("[WARNING]", ".env: %s", p)
+			}
+		}
+		if len(problems) == 0 {
+			report("[OK]", ".env")
+		}
+	}
+
+	list, err := plugins.List(filepath.Join(l.appDir, "plugins"))
+	if err != nil {
+		report("[WARNING]", "Plugins nicht lesbar: %v", err)
+	}
+	clean := 0
+	for _, p := range list {
+		if len(p.Problems) == 0 {
+			clean++
 			continue
 		}
-		
-		// Handle headers
-		if strings.HasPrefix(line, "## ") {
-			if inList {
-				html.WriteString("</ul>")
-				inList = false
+		for _, prob := range p.Problems {
+			// Disabled plugins are never loaded, their problems can wait
+			if prob.Critical && p.Enabled {
+				report("[ERROR]", "Plugin %s: %s", p.ID, prob)
+			} else if p.Enabled {
+				report("[WARNING]", "Plugin %s: %s", p.ID, prob)
+			} else {
+				report("[WARNING]", "Plugin %s (deaktiviert): %s", p.ID, prob)
 			}
-			version := strings.TrimPrefix(line, "## ")
-			html.WriteString(fmt.Sprintf("<div class='changelog-version'>%s</div>", template.HTMLEscapeString(version)))
-		} else if strings.HasPrefix(line, "### ") {
-			if inList {
-				html.WriteString("</ul>")
-				inList = false
+		}
+	}
+	if len(list) > 0 {
+		report("[OK]", "%d von %d Plugins ohne Befund", clean, len(list))
+	}
+
+	// Where the last starts spent their time
+	if runs, err := startprofile.Load(l.configDir()); err != nil {
+		report("[WARNING]", "Startzeiten nicht lesbar: %v", err)
+	} else if len(runs) > 0 {
+		last := runs[len(runs)-1]
+		pauses := "ohne Anzeigepausen (--fast)"
+		if !last.Fast {
+			pauses = fmt.Sprintf("davon %s Anzeigepausen, --fast überspringt sie", startprofile.Seconds(last.PauseMs()))
+		}
+		report("[INFO]", "Letzter Start am %s: %s, %s", last.Started.Local().Format("02.01.2006 15:04"), startprofile.Seconds(last.TotalMs), pauses)
+		for _, p := range last.Phases {
+			fmt.Printf("%-10s   %-32s %9s\n", "", startprofile.Label(p.Name), startprofile.Seconds(p.Ms))
+		}
+		if len(runs) > 1 {
+			var total int64
+			for _, run := range runs {
+				total += run.TotalMs
 			}
-			title := strings.TrimPrefix(line, "### ")
-			html.WriteString(fmt.Sprintf("<h3>%s</h3>", template.HTMLEscapeString(title)))
-		} else if strings.HasPrefix(line, "- ") {
-			if !inList {
-				html.WriteString("<ul>")
-				inList = true
+			report("[INFO]", "Durchschnitt der letzten %d Starts: %s", len(runs), startprofile.Seconds(total/int64(len(runs))))
+		}
+	}
+
+	if errorCount > 0 {
+		fmt.Printf("\n%d Fehler gefunden\n", errorCount)
+		return exitFailure
+	}
+	fmt.Println("\nKeine Fehler gefunden")
+	return exitOK
+}
+
+// installPlugins installs every zip or URL in sources, or with update
+// reinstalls the given plugin ids from the source recorded at install time.
+func installPlugins(dir string, update bool, sources []string, npm plugins.NPMFunc) int {
+	code := exitOK
+	for _, arg := range sources {
+		source, replace := arg, ""
+		if update {
+			replace = arg
+			p, err := plugins.Find(dir, arg)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "[ERROR]", err)
+				code = exitFailure
+				continue
 			}
-			item := strings.TrimPrefix(line, "- ")
-			// Handle bold text **text** by replacing pairs of **
-			for strings.Contains(item, "**") {
-				// Find first pair and replace
-				firstPos := strings.Index(item, "**")
-				if firstPos != -1 {
-					// Replace first ** with <strong>
-					item = item[:firstPos] + "<strong>" + item[firstPos+2:]
-					// Find next ** and replace with </strong>
-					secondPos := strings.Index(item[firstPos:], "**")
-					if secondPos != -1 {
-						actualPos := firstPos + secondPos
-						item = item[:actualPos] + "</strong>" + item[actualPos+2:]
-					} else {
-						// Unmatched **, revert the change
-						item = strings.Replace(item, "<strong>", "**", 1)
-						break
-					}
-				} else {
-					break
-				}
+			src, err := plugins.ReadSource(p.Dir)
+			if err == nil && src == nil {
+				err = fmt.Errorf("Plugin %s gehört zu LTTH und wird mit der App aktualisiert", arg)
 			}
-			html.WriteString(fmt.Sprintf("<li>%s</li>", item))
-		} else if strings.TrimSpace(line) == "" {
-			if inList {
-				html.WriteString("</ul>")
-				inList = false
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "[ERROR]", err)
+				code = exitFailure
+				continue
 			}
-		} else if !strings.HasPrefix(line, "[") {
-			// Regular paragraph
-			if inList {
-				html.WriteString("</ul>")
-				inList = false
+			source = src.Source
+		}
+
+		zipPath, download := source, ""
+		if plugins.IsURL(source) {
+			fmt.Printf("[INFO] Lade %s herunter...\n", source)
+			tmp, err := plugins.Download(source)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "[ERROR]", err)
+				code = exitFailure
+				continue
 			}
-			if strings.TrimSpace(line) != "" {
-				html.WriteString(fmt.Sprintf("<p>%s</p>", template.HTMLEscapeString(line)))
+			zipPath, download = tmp, tmp
+		} else if abs, err := filepath.Abs(source); err == nil {
+			source, zipPath = abs, abs
+		}
+
+		p, err := plugins.Install(dir, zipPath, source, replace, npm)
+		if download != "" {
+			os.Remove(download)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR]", err)
+			code = exitFailure
+			continue
+		}
+		for _, prob := range p.Problems {
+			fmt.Printf("[WARNING] Plugin %s: %s\n", p.ID, prob)
+		}
+		if update {
+			fmt.Printf("[SUCCESS] Plugin %s auf Version %s aktualisiert\n", p.ID, p.Version)
+		} else if p.Enabled {
+			fmt.Printf("[SUCCESS] Plugin %s %s installiert und aktiviert\n", p.ID, p.Version)
+		} else {
+			fmt.Printf("[SUCCESS] Plugin %s %s installiert (deaktiviert: launcher plugins enable %s)\n", p.ID, p.Version, p.ID)
+		}
+	}
+	return code
+}
+
+// pluginNPM installs a plugin's missing npm packages into its own
+// node_modules, app/package.json stays untouched. npm runs in the app
+// directory like the main npm install, so relative npm args match.
+func pluginNPM(appDir string, npmArgs []string) plugins.NPMFunc {
+	return func(pluginDir string, packages []string) error {
+		args := append([]string{"install", "--prefix", pluginDir, "--no-save", "--no-package-lock", "--omit=dev"}, npmArgs...)
+		return runNPM(appDir, append(args, packages...)...)
+	}
+}
+
+// runNPM runs npm in dir for a command-line subcommand, output goes to
+// the console
+func runNPM(dir string, args ...string) error {
+	fmt.Printf("[INFO] npm %s\n", strings.Join(args, " "))
+	cmd := procutil.NPM(args...)
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// npmArgs are the extra arguments of every npm install: the cache of the
+// installation (offline-first once it holds packages) followed by the
+// configured npmArgs. A --cache in npmArgs replaces the installation cache.
+func npmArgs(cfg config.Config, exeDir string) []string {
+	for _, a := range cfg.NpmArgs {
+		if a == "--cache" || strings.HasPrefix(a, "--cache=") {
+			return cfg.NpmArgs
+		}
+	}
+	return append(npmcache.Args(cfg.ResolveNpmCache(exeDir)), cfg.NpmArgs...)
+}
+
+// runCacheCommand handles "launcher cache [status]", "prefetch",
+// "prune [--all]", "export DATEI" and "import DATEI" for the npm cache
+// of the installation.
+func runCacheCommand(exeDir string, args []string) int {
+	usage := "Verwendung: launcher cache status|prefetch|prune [--all]|export DATEI|import DATEI [--npm-cache DIR]"
+	action := "status"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action, args = args[0], args[1:]
+	}
+	fs := flag.NewFlagSet("cache", flag.ContinueOnError)
+	all := fs.Bool("all", false, "prune: den ganzen Cache löschen statt nur ungenutzte Daten")
+	cfg, cfgErr := config.Load(exeDir)
+	cfg.RegisterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitConfig
+	}
+	if cfgErr == nil {
+		cfgErr = cfg.Validate()
+	}
+	if cfgErr != nil {
+		fmt.Fprintln(os.Stderr, "[ERROR] Ungültige Launcher-Konfiguration:", cfgErr)
+		return exitConfig
+	}
+	dir := cfg.ResolveNpmCache(exeDir)
+	if dir == "" {
+		fmt.Fprintln(os.Stderr, "[ERROR] Kein npm-Cache für die Installation eingestellt (npmCache ist leer)")
+		return exitConfig
+	}
+	appDir := cfg.ResolveAppDir(exeDir)
+
+	switch action {
+	case "status":
+		u, err := npmcache.Size(dir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR]", err)
+			return exitFailure
+		}
+		state := "leer - npm install lädt alle Pakete aus dem Internet"
+		if npmcache.Warm(dir) {
+			state = "gefüllt - npm install nutzt zuerst den Cache (--prefer-offline)"
+		}
+		fmt.Printf("npm-Cache:  %s\n", dir)
+		fmt.Printf("Größe:      %s (%d Dateien)\n", formatBytes(u.Bytes), u.Files)
+		fmt.Printf("Status:     %s\n", state)
+		return exitOK
+
+	case "prefetch":
+		specs, err := npmcache.LockedPackages(appDir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR]", err)
+			return exitFailure
+		}
+		fmt.Printf("[INFO] Lade %d Pakete aus package-lock.json in den Cache\n", len(specs))
+		// Batches keep the command line below the Windows limit
+		for i := 0; i < len(specs); i += 50 {
+			batch := specs[i:min(i+50, len(specs))]
+			if err := runNPM(appDir, append([]string{"cache", "add", "--cache", dir}, batch...)...); err != nil {
+				fmt.Fprintln(os.Stderr, "[ERROR] npm cache add fehlgeschlagen:", err)
+				return exitFailure
 			}
 		}
+		u, _ := npmcache.Size(dir)
+		fmt.Printf("[SUCCESS] %d Pakete im Cache, %s\n", len(specs), formatBytes(u.Bytes))
+		return exitOK
+
+	case "export":
+		if fs.NArg() != 1 {
+			fmt.Fprintln(os.Stderr, usage)
+			return exitConfig
+		}
+		u, err := npmcache.Export(dir, fs.Arg(0))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "[ERROR] Export fehlgeschlagen:", err)
+			return exitFailure
+		}
+		fmt.Printf("[SUCCESS] %d Dateien (%s) nach %s exportiert\n", u.Files, formatBytes(u.Bytes), fs.Arg(0))
+		return exitOK
+
+	case "prune", "import":
+		if action == "import" && fs.NArg() != 1 {
+			fmt.Fprintln(os.Stderr, usage)
+			return exitConfig
+		}
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		return exitConfig
 	}
-	
-	if inList {
-		html.WriteString("</ul>")
+
+	// A starting launcher may be running npm install on the cache
+	if info, err := instancelock.Read(appDir); err == nil && procutil.Alive(info.PID) {
+		fmt.Fprintf(os.Stderr, "[ERROR] Launcher läuft (PID %d) - bitte erst beenden\n", info.PID)
+		return exitAlreadyRunning
 	}
-	
-	return html.String()
+	before, _ := npmcache.Size(dir)
+	var err error
+	switch {
+	case action == "import":
+		err = npmcache.Import(fs.Arg(0), dir)
+	case *all:
+		err = os.RemoveAll(dir)
+	default:
+		// Removes data no index entry points to and corrupted entries
+		err = runNPM(appDir, "cache", "verify", "--cache", dir)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] npm-Cache: %v\n", err)
+		return exitFailure
+	}
+	after, _ := npmcache.Size(dir)
+	if action == "import" {
+		fmt.Printf("[SUCCESS] Cache importiert, jetzt %s (%d Dateien)\n", formatBytes(after.Bytes), after.Files)
+	} else {
+		fmt.Printf("[SUCCESS] %s freigegeben, Cache jetzt %s\n", formatBytes(before.Bytes-after.Bytes), formatBytes(after.Bytes))
+	}
+	return exitOK
+}
+
+// formatBytes returns a human readable size
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
 }
 
 func main() {
@@ -782,22 +3373,128 @@ func main() {
 	}
 
 	exeDir := filepath.Dir(exePath)
-	launcher.appDir = filepath.Join(exeDir, "app")
+
+	// Subcommands run and exit without starting the server
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case selfupdate.SelftestFlag:
+			// Run by the previous launcher before it hands over to this build
+			fmt.Println(selfupdate.SelftestPrefix + launcherVersion)
+			os.Exit(exitOK)
+		case "service":
+			procutil.AttachParentConsole()
+			os.Exit(runServiceCommand(exePath, os.Args[2:]))
+		case "restore":
+			procutil.AttachParentConsole()
+			os.Exit(runRestoreCommand(exeDir, os.Args[2:]))
+		case "plugins", "plugin":
+			procutil.AttachParentConsole()
+			os.Exit(runPluginsCommand(exeDir, os.Args[2:]))
+		case "doctor":
+			procutil.AttachParentConsole()
+			os.Exit(runDoctorCommand(exeDir, os.Args[2:]))
+		case "cache":
+			procutil.AttachParentConsole()
+			os.Exit(runCacheCommand(exeDir, os.Args[2:]))
+		}
+	}
+
+	// Settings: defaults < launcher.json < command-line flags
+	cfg, cfgErr := config.Load(exeDir)
+	cfg.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+	if cfgErr == nil {
+		cfgErr = cfg.Validate()
+	}
+	if cfgErr != nil {
+		if cfg.Headless {
+			// A service must not silently run with different settings
+			fmt.Fprintln(os.Stderr, "[ERROR] Ungültige Launcher-Konfiguration:", cfgErr)
+			os.Exit(exitConfig)
+		}
+		// Drop launcher.json but keep the command-line flags, unless they
+		// are what is invalid
+		cfg = config.Default()
+		flags := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+		flags.SetOutput(io.Discard)
+		cfg.RegisterFlags(flags)
+		if flags.Parse(os.Args[1:]) != nil || cfg.Validate() != nil {
+			cfg = config.Default()
+		}
+	}
+	if cfg.Headless {
+		cfg.OpenBrowser = false
+		launcher.console = logsink.New(os.Stdout, logsink.UnderJournald(), logsink.PriorityInfo)
+	}
+	launcher.cfg = cfg
+	launcher.port = cfg.ServerPort
+	launcher.timing = startprofile.New(cfg.Fast)
+	launcher.limit = watchdog.RestartLimit{Max: cfg.WatchdogRestarts, Window: time.Hour}
+
+	launcher.exeDir = exeDir
+	launcher.appDir = cfg.ResolveAppDir(exeDir)
+	launcher.appVersion = changelog.AppVersion(launcher.appDir)
+	launcher.lastVersion = changelog.LastVersion(launcher.configDir())
+	if runs, _ := startprofile.Load(launcher.configDir()); len(runs) > 0 {
+		launcher.lastStart = &runs[len(runs)-1]
+	}
 	bgImagePath := filepath.Join(launcher.appDir, "launcherbg.jpg")
 
+	// Only one launcher per installation - a second launch just reopens the
+	// running instance instead of racing it for the splash port and npm install
+	guard, err := webguard.New(launcher.splashAddr())
+	if err != nil {
+		log.Fatal("Kann Launcher-Token nicht erzeugen:", err)
+	}
+	launcher.guard = guard
+	splashURL := guard.URL("http://" + launcher.splashAddr())
+
+	lock, lockErr := instancelock.Acquire(launcher.appDir, splashURL)
+	var held *instancelock.HeldError
+	if errors.As(lockErr, &held) {
+		if cfg.Headless {
+			fmt.Fprintf(os.Stderr, "[ERROR] Launcher läuft bereits (PID %d)\n", held.Info.PID)
+			os.Exit(exitAlreadyRunning)
+		}
+		url := held.Info.URL
+		if url == "" {
+			url = "http://" + launcher.splashAddr()
+		}
+		if cfg.OpenBrowser {
+			browser.OpenURL(url)
+		}
+		os.Exit(exitOK)
+	}
+	launcher.lock = lock
+
 	// Setup logging immediately
 	if err := launcher.setupLogging(launcher.appDir); err != nil {
 		// If logging fails, create a fallback logger that does nothing
 		// (since stdout doesn't exist in GUI mode)
 		launcher.logger = log.New(io.Discard, "", log.LstdFlags)
+		if launcher.console != nil {
+			launcher.logger.SetOutput(launcher.console)
+			launcher.logAndSync("[WARNING] Could not create log file: %v", err)
+		}
 	}
 
 	launcher.logAndSync("Launcher started successfully")
 	launcher.logAndSync("Executable directory: %s", exeDir)
 	launcher.logAndSync("App directory: %s", launcher.appDir)
+	if cfgErr != nil {
+		launcher.logAndSync("[WARNING] Invalid launcher configuration, using defaults: %v", cfgErr)
+	}
+	if lockErr != nil {
+		launcher.logAndSync("[WARNING] Could not create launcher.lock, continuing without single-instance lock: %v", lockErr)
+	} else if err := lock.SetToken(guard.Token()); err != nil {
+		launcher.logAndSync("[WARNING] Could not store token in launcher.lock: %v", err)
+	}
+	launcher.applyLauncherUpdate(exePath)
 
 	// Setup HTTP server
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+	// Every route checks the Host header; pages need the per-launch token
+	// cookie and anything that changes state must be a same-origin POST
+	http.Handle("/", guard.Page(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		tmpl := template.Must(template.New("index").Parse(`
 <!DOCTYPE html>
 <html>
@@ -910,6 +3607,40 @@ func main() {
             box-shadow: 0 2px 4px rgba(102, 126, 234, 0.3);
         }
         
+        /* Phase times below the progress bar */
+        .timing {
+            margin-top: 12px;
+            font-size: 12px;
+            color: #555;
+            flex-shrink: 0;
+        }
+        
+        .timing summary {
+            cursor: pointer;
+            font-weight: 600;
+            color: #333;
+        }
+        
+        .timing table {
+            width: 100%;
+            margin-top: 6px;
+            border-collapse: collapse;
+        }
+        
+        .timing td {
+            padding: 2px 0;
+        }
+        
+        .timing td + td {
+            text-align: right;
+            white-space: nowrap;
+            padding-left: 8px;
+        }
+        
+        .timing-last {
+            color: #999;
+        }
+        
         /* Center changelog area */
         .changelog-container {
             grid-column: 1 / 3;
@@ -943,7 +3674,8 @@ func main() {
             font-size: 18px;
         }
         
-        .changelog-content ul {
+        .changelog-content ul,
+        .changelog-content ol {
             margin-left: 20px;
             margin-bottom: 10px;
         }
@@ -952,7 +3684,13 @@ func main() {
             margin-bottom: 5px;
         }
         
-        .changelog-version {
+        .changelog-content li > ul,
+        .changelog-content li > ol {
+            margin-top: 5px;
+            margin-bottom: 0;
+        }
+        
+        .changelog-content h2 {
             color: #764ba2;
             font-weight: bold;
             font-size: 16px;
@@ -960,6 +3698,119 @@ func main() {
             margin-bottom: 10px;
         }
         
+        .changelog-content a {
+            color: #667eea;
+        }
+        
+        .changelog-content code {
+            background-color: #f0f0f5;
+            border-radius: 3px;
+            padding: 1px 4px;
+            font-size: 13px;
+        }
+        
+        .changelog-content pre {
+            background-color: #f0f0f5;
+            border-radius: 5px;
+            padding: 10px;
+            overflow-x: auto;
+            margin-bottom: 10px;
+        }
+        
+        .changelog-content pre code {
+            padding: 0;
+        }
+        
+        .changelog-new {
+            background-color: #f3f0ff;
+            border-left: 4px solid #764ba2;
+            border-radius: 5px;
+            padding: 5px 15px 10px;
+            margin-bottom: 15px;
+        }
+        
+        .changelog-new-title {
+            color: #764ba2;
+            font-weight: bold;
+            font-size: 18px;
+            margin-top: 10px;
+        }
+        
+        .changelog-none {
+            color: #999;
+            margin-bottom: 10px;
+        }
+        
+        .changelog-history summary {
+            cursor: pointer;
+            color: #667eea;
+            font-weight: bold;
+        }
+        
+        .changelog-content blockquote {
+            border-left: 3px solid #667eea;
+            padding-left: 10px;
+            color: #777;
+        }
+        
+        /* Profile picker, shown in place of the changelog */
+        .profile-container {
+            display: none;
+        }
+        
+        .profile-hint {
+            color: #764ba2;
+            font-weight: 600;
+            margin-bottom: 15px;
+        }
+        
+        .profile-item {
+            display: flex;
+            justify-content: space-between;
+            align-items: center;
+            width: 100%;
+            padding: 12px 16px;
+            margin-bottom: 10px;
+            background: white;
+            border: 2px solid #e0e0e0;
+            border-radius: 8px;
+            font-size: 15px;
+            cursor: pointer;
+            transition: border-color 0.2s;
+        }
+        
+        .profile-item:hover,
+        .profile-item.active {
+            border-color: #667eea;
+        }
+        
+        .profile-name {
+            font-weight: bold;
+            color: #333;
+        }
+        
+        .profile-used {
+            color: #999;
+            font-size: 13px;
+        }
+        
+        /* Offer to move an unwritable config directory, same place */
+        .relocate-text {
+            color: #555;
+            margin-bottom: 10px;
+            word-break: break-all;
+        }
+        
+        .relocate-dir {
+            width: 100%;
+            box-sizing: border-box;
+            padding: 10px 12px;
+            margin-bottom: 10px;
+            border: 2px solid #e0e0e0;
+            border-radius: 8px;
+            font-size: 14px;
+        }
+        
         /* Bottom-right links */
         .links-container {
             grid-column: 1 / 4;
@@ -1036,13 +3887,36 @@ func main() {
             <div class="progress-bar-bg">
                 <div class="progress-bar-fill" id="progressBar">0%</div>
             </div>
+            <details class="timing" id="timingPanel" style="display: none;">
+                <summary id="timingSummary">⏱️ Startzeiten</summary>
+                <table>
+                    <thead><tr><td></td><td>jetzt</td><td class="timing-last">letzter Start</td></tr></thead>
+                    <tbody id="timingRows"></tbody>
+                </table>
+                <div class="timing-last" id="timingPauses"></div>
+            </details>
         </div>
         
         <!-- Center changelog area -->
         <div class="changelog-container">
-            <div class="changelog-title">📝 Changelog</div>
-            <div class="changelog-content" id="changelog">
-                <p style="color: #999;">Lade Changelog...</p>
+            <div id="changelogPanel">
+                <div class="changelog-title">📝 Changelog</div>
+                <div class="changelog-content" id="changelog">
+                    <p style="color: #999;">Lade Changelog...</p>
+                </div>
+            </div>
+            <div class="profile-container" id="profilePanel">
+                <div class="changelog-title">👤 Wer streamt heute?</div>
+                <div class="profile-hint" id="profileHint"></div>
+                <div id="profileList"></div>
+            </div>
+            <div class="profile-container" id="relocatePanel">
+                <div class="changelog-title">📁 Konfigurationsordner nicht beschreibbar</div>
+                <div class="relocate-text">In <code id="relocateFrom"></code> können Profile und Einstellungen nicht gespeichert werden. In diesen Ordner verlegen? Vorhandene Daten werden kopiert, der Pfad wird in app/.config_path gespeichert.</div>
+                <input type="text" class="relocate-dir" id="relocateDir">
+                <div class="profile-hint" id="relocateHint"></div>
+                <button class="profile-item" onclick="relocateConfig(false)"><span class="profile-name">Verlegen</span></button>
+                <button class="profile-item" onclick="relocateConfig(true)"><span class="profile-used">Ohne Verlegen fortfahren</span></button>
             </div>
         </div>
         
@@ -1056,6 +3930,14 @@ func main() {
                 <span class="link-icon">💜</span>
                 <span>Discord Community</span>
             </a>
+            <a href="/plugins" target="_blank" class="link-item">
+                <span class="link-icon">🧩</span>
+                <span>Plugins</span>
+            </a>
+            <a href="/settings" target="_blank" class="link-item">
+                <span class="link-icon">⚙️</span>
+                <span>Einstellungen</span>
+            </a>
         </div>
     </div>
     
@@ -1075,6 +3957,21 @@ func main() {
                 return;
             }
             
+            if (data.pickProfile) {
+                showProfilePicker();
+                return;
+            }
+            
+            if (data.relocateConfig) {
+                showRelocation();
+                return;
+            }
+            
+            if (data.timing) {
+                loadTiming();
+                return;
+            }
+            
             // Handle progress updates
             const progressBar = document.getElementById('progressBar');
             const statusText = document.getElementById('status');
@@ -1084,6 +3981,154 @@ func main() {
             statusText.textContent = data.status;
         };
         
+        // Profile picker: the launcher waits for a choice before starting the server
+        let profileTimer = null;
+        
+        function hideProfilePicker() {
+            clearInterval(profileTimer);
+            document.getElementById('profilePanel').style.display = 'none';
+            document.getElementById('changelogPanel').style.display = 'block';
+        }
+        
+        function showProfilePicker() {
+            fetch('/api/launcher/profiles')
+                .then(response => response.json())
+                .then(data => {
+                    if (!data.picking) {
+                        return;
+                    }
+                    const list = document.getElementById('profileList');
+                    list.innerHTML = '';
+                    data.profiles.forEach(function(profile) {
+                        const item = document.createElement('button');
+                        item.className = 'profile-item' + (profile.name === data.active ? ' active' : '');
+                        const name = document.createElement('span');
+                        name.className = 'profile-name';
+                        name.textContent = profile.name;
+                        const used = document.createElement('span');
+                        used.className = 'profile-used';
+                        used.textContent = 'Zuletzt verwendet: ' + new Date(profile.lastUsed).toLocaleString('de-DE');
+                        item.appendChild(name);
+                        item.appendChild(used);
+                        item.onclick = function() { pickProfile(profile.name); };
+                        list.appendChild(item);
+                    });
+                    document.getElementById('changelogPanel').style.display = 'none';
+                    document.getElementById('profilePanel').style.display = 'block';
+                    
+                    const hint = document.getElementById('profileHint');
+                    let remaining = data.remainingSeconds;
+                    const tick = function() {
+                        if (remaining <= 0) {
+                            hideProfilePicker();
+                            return;
+                        }
+                        hint.textContent = data.active
+                            ? 'Start mit "' + data.active + '" in ' + remaining + ' Sekunden'
+                            : 'Start in ' + remaining + ' Sekunden';
+                        remaining--;
+                    };
+                    clearInterval(profileTimer);
+                    profileTimer = setInterval(tick, 1000);
+                    tick();
+                });
+        }
+        
+        function pickProfile(name) {
+            fetch('/api/launcher/profile', {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/x-www-form-urlencoded' },
+                body: 'name=' + encodeURIComponent(name)
+            })
+                .then(response => response.json())
+                .then(data => {
+                    if (data.success) {
+                        hideProfilePicker();
+                    } else {
+                        document.getElementById('profileHint').textContent = data.error;
+                    }
+                });
+        }
+        
+        // The picker may already be open when the page is (re)loaded
+        showProfilePicker();
+        
+        // Config directory not writable: the launcher waits for a decision
+        function showRelocation() {
+            fetch('/api/launcher/config-relocation')
+                .then(response => response.json())
+                .then(data => {
+                    if (!data.offered) {
+                        return;
+                    }
+                    document.getElementById('relocateFrom').textContent = data.from;
+                    document.getElementById('relocateDir').value = data.to;
+                    document.getElementById('relocateHint').textContent =
+                        'Ohne Auswahl geht der Start in ' + Math.ceil(data.remainingSeconds / 60) + ' Minute(n) ohne Verlegen weiter';
+                    document.getElementById('changelogPanel').style.display = 'none';
+                    document.getElementById('relocatePanel').style.display = 'block';
+                });
+        }
+        
+        function relocateConfig(skip) {
+            const body = skip ? 'skip=1' : 'dir=' + encodeURIComponent(document.getElementById('relocateDir').value);
+            fetch('/api/launcher/config-dir', {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/x-www-form-urlencoded' },
+                body: body
+            })
+                .then(response => response.json())
+                .then(data => {
+                    if (data.success) {
+                        document.getElementById('relocatePanel').style.display = 'none';
+                        document.getElementById('changelogPanel').style.display = 'block';
+                    } else {
+                        document.getElementById('relocateHint').textContent = data.error;
+                    }
+                });
+        }
+        
+        showRelocation();
+        
+        // Phase times of this start next to those of the previous one
+        function seconds(ms) {
+            return (ms / 1000).toFixed(1) + ' s';
+        }
+        
+        function loadTiming() {
+            fetch('/api/launcher/startup-profile')
+                .then(response => response.json())
+                .then(data => {
+                    if (data.phases.length === 0) {
+                        return;
+                    }
+                    const rows = document.getElementById('timingRows');
+                    rows.innerHTML = '';
+                    data.phases.forEach(function(phase) {
+                        const row = document.createElement('tr');
+                        [phase.label, seconds(phase.ms), phase.lastMs === undefined ? '–' : seconds(phase.lastMs)].forEach(function(text, i) {
+                            const cell = document.createElement('td');
+                            cell.textContent = text;
+                            if (i === 2) {
+                                cell.className = 'timing-last';
+                            }
+                            row.appendChild(cell);
+                        });
+                        rows.appendChild(row);
+                    });
+                    let summary = '⏱️ Startzeiten: ' + seconds(data.totalMs);
+                    if (data.lastTotalMs) {
+                        summary += ' (letzter Start ' + seconds(data.lastTotalMs) + ')';
+                    }
+                    document.getElementById('timingSummary').textContent = summary;
+                    document.getElementById('timingPauses').textContent = data.fast ? 'Schnellstart (--fast): ohne Anzeigepausen' :
+                        'davon ' + seconds(data.pauseMs) + ' Anzeigepausen, --fast überspringt sie';
+                    document.getElementById('timingPanel').style.display = 'block';
+                });
+        }
+        
+        loadTiming();
+        
         // Load changelog
         // Note: This content is from our own CHANGELOG.md file served by the launcher,
         // so it's safe to use innerHTML. It's not user-generated content.
@@ -1100,61 +4145,52 @@ func main() {
 </html>
 `))
 		tmpl.Execute(w, nil)
-	})
+	})))
 
-	http.HandleFunc("/bg", func(w http.ResponseWriter, r *http.Request) {
-		http.ServeFile(w, r, bgImagePath)
-	})
+	http.Handle("/settings", guard.Page(http.HandlerFunc(launcher.handleSettings)))
+	http.Handle("/plugins", guard.Page(http.HandlerFunc(launcher.handlePlugins)))
 
-	http.HandleFunc("/changelog", func(w http.ResponseWriter, r *http.Request) {
-		changelogPath := filepath.Join(exeDir, "CHANGELOG.md")
-		content, err := os.ReadFile(changelogPath)
+	// Control API for the dashboard and scripts (Stream Deck etc.)
+	http.Handle("/api/launcher/status", guard.Page(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, launcher.controlStatus())
+	})))
+	http.Handle("/api/launcher/profiles", guard.Page(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		list, err := launcher.listProfiles()
 		if err != nil {
-			w.Header().Set("Content-Type", "text/html; charset=utf-8")
-			w.Write([]byte("<p style='color: #999;'>Changelog konnte nicht geladen werden.</p>"))
+			writeJSON(w, http.StatusInternalServerError, map[string]interface{}{"success": false, "error": err.Error()})
 			return
 		}
-		
-		// Parse markdown and convert to HTML (simple conversion)
-		html := parseChangelogToHTML(string(content))
-		w.Header().Set("Content-Type", "text/html; charset=utf-8")
-		w.Write([]byte(html))
-	})
-
-	http.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "text/event-stream")
-		w.Header().Set("Cache-Control", "no-cache")
-		w.Header().Set("Connection", "keep-alive")
+		writeJSON(w, http.StatusOK, list)
+	})))
+	http.Handle("/api/launcher/config-relocation", guard.Page(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, launcher.currentRelocation())
+	})))
+	http.Handle("/api/launcher/startup-profile", guard.Page(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, launcher.startProfile())
+	})))
+	http.Handle("/api/launcher/changelog", guard.Page(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		view, err := launcher.loadChangelog()
+		if err != nil {
+			writeJSON(w, http.StatusNotFound, map[string]interface{}{"success": false, "error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, view)
+	})))
+	http.Handle("/api/launcher/", guard.Action(http.HandlerFunc(launcher.handleControl)))
 
-		client := make(chan string, 10)
-		launcher.clients[client] = true
+	http.Handle("/bg", guard.Public(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, bgImagePath)
+	})))
 
-		// Send initial state
-		msg := fmt.Sprintf(`{"progress": %d, "status": "%s"}`, launcher.progress, launcher.status)
-		fmt.Fprintf(w, "data: %s\n\n", msg)
-		if f, ok := w.(http.Flusher); ok {
-			f.Flush()
-		}
+	http.Handle("/changelog", guard.Page(http.HandlerFunc(launcher.handleChangelog)))
 
-		// Listen for updates
-		for {
-			select {
-			case msg := <-client:
-				fmt.Fprintf(w, "data: %s\n\n", msg)
-				if f, ok := w.(http.Flusher); ok {
-					f.Flush()
-				}
-			case <-r.Context().Done():
-				delete(launcher.clients, client)
-				return
-			}
-		}
-	})
+	http.Handle("/events", guard.Page(launcher.events))
 
 	// Start HTTP server
 	go func() {
-		if err := http.ListenAndServe("127.0.0.1:58734", nil); err != nil {
-			log.Fatal(err)
+		if err := http.ListenAndServe(launcher.splashAddr(), nil); err != nil {
+			launcher.logAndSync("[ERROR] Launcher HTTP server failed: %v", err)
+			launcher.shutdown(exitFailure)
 		}
 	}()
 
@@ -1162,7 +4198,15 @@ func main() {
 	time.Sleep(500 * time.Millisecond)
 
 	// Open browser
-	browser.OpenURL("http://127.0.0.1:58734")
+	if cfg.OpenBrowser {
+		browser.OpenURL(splashURL)
+	}
+	if cfg.Headless {
+		launcher.logAndSync("[INFO] Headless mode - control API on http://%s (token in %s)", launcher.splashAddr(), filepath.Join(launcher.appDir, instancelock.FileName))
+	}
+
+	// Forward Ctrl+C / SIGTERM / console close to the server
+	launcher.handleSignals()
 
 	// Run launcher
 	go launcher.runLauncher()
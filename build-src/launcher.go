@@ -5,8 +5,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
-	"syscall"
+
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/npmcache"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/procutil"
 )
 
 const (
@@ -15,9 +16,6 @@ const (
 	colorGreen  = "\033[32m"
 	colorYellow = "\033[33m"
 	colorCyan   = "\033[36m"
-	
-	// CREATE_NO_WINDOW flag for Windows to hide console window
-	createNoWindow = 0x08000000
 )
 
 func printHeader() {
@@ -56,16 +54,9 @@ func checkNodeModules(appDir string) bool {
 func installDependencies(appDir string) error {
 	fmt.Println("Installiere Abhaengigkeiten... (Das kann beim ersten Start ein paar Minuten dauern)")
 	
-	var cmd *exec.Cmd
-	if runtime.GOOS == "windows" {
-		cmd = exec.Command("cmd", "/C", "npm", "install", "--cache", "false")
-		// Hide the npm install window on Windows using CREATE_NO_WINDOW flag
-		cmd.SysProcAttr = &syscall.SysProcAttr{
-			CreationFlags: createNoWindow,
-		}
-	} else {
-		cmd = exec.Command("npm", "install", "--cache", "false")
-	}
+	// npm runs without a console window of its own
+	args := append([]string{"install"}, npmcache.Args(filepath.Join(filepath.Dir(appDir), npmcache.DirName))...)
+	cmd := procutil.NPM(args...)
 	
 	cmd.Dir = appDir
 	// Don't show npm install output in the console
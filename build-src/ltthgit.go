@@ -1,8 +1,8 @@
 package main
 
 import (
-	"archive/zip"
 	"embed"
+	"errors"
 	"fmt"
 	"html/template"
 	"io"
@@ -11,9 +11,14 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
 	"time"
 
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/backup"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/config"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/npmcache"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/preflight"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/procutil"
+	"github.com/Loggableim/pupcidslittletiktokhelper/internal/unzip"
 	"github.com/pkg/browser"
 )
 
@@ -155,10 +160,13 @@ func (cl *CloudLauncher) downloadRepository() error {
 		return fmt.Errorf("Speichern fehlgeschlagen: %v", err)
 	}
 
+	// Snapshot user settings before the archive overwrites the app tree
+	cl.backupConfig()
+
 	cl.updateProgress(50, "Extrahiere Dateien...")
 
 	// Extract ZIP
-	err = cl.extractZip(tempZip.Name(), cl.baseDir)
+	err = unzip.Extract(tempZip.Name(), cl.baseDir, true)
 	if err != nil {
 		return fmt.Errorf("Extraktion fehlgeschlagen: %v", err)
 	}
@@ -167,69 +175,33 @@ func (cl *CloudLauncher) downloadRepository() error {
 	return nil
 }
 
-// Extract ZIP file
-func (cl *CloudLauncher) extractZip(zipPath, destDir string) error {
-	r, err := zip.OpenReader(zipPath)
+// backupConfig saves .env, user_configs, user_data and the
+// ConfigPathManager directory, keeping as many snapshots as launcher.json allows
+func (cl *CloudLauncher) backupConfig() {
+	cfg, err := config.Load(cl.baseDir)
 	if err != nil {
-		return err
+		// Load returns the parsed values even when they fail validation
+		cl.logger.Printf("[WARNING] %v - using default backup settings\n", err)
+		cfg = config.Default()
 	}
-	defer r.Close()
-
-	for _, f := range r.File {
-		// Skip the root directory (e.g., "pupcidslittletiktokhelper-main/")
-		// Extract directly to destDir
-		fpath := f.Name
-		
-		// Remove first directory component
-		idx := 0
-		for i, c := range fpath {
-			if c == '/' || c == '\\' {
-				idx = i + 1
-				break
-			}
-		}
-		if idx > 0 {
-			fpath = fpath[idx:]
-		}
-		
-		if fpath == "" {
-			continue
-		}
-
-		fullPath := filepath.Join(destDir, fpath)
-
-		if f.FileInfo().IsDir() {
-			os.MkdirAll(fullPath, os.ModePerm)
-			continue
-		}
-
-		// Create parent directories
-		if err := os.MkdirAll(filepath.Dir(fullPath), os.ModePerm); err != nil {
-			return err
-		}
-
-		// Extract file
-		outFile, err := os.OpenFile(fullPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
-		if err != nil {
-			return err
-		}
-
-		rc, err := f.Open()
-		if err != nil {
-			outFile.Close()
-			return err
-		}
-
-		_, err = io.Copy(outFile, rc)
-		outFile.Close()
-		rc.Close()
-
-		if err != nil {
-			return err
-		}
+	if cfg.BackupKeep == 0 {
+		return
 	}
 
-	return nil
+	appDir := filepath.Join(cl.baseDir, "app")
+	dir := cfg.ResolveBackupDir(cl.baseDir)
+	cl.updateProgress(45, "Sichere Einstellungen...")
+	path, err := backup.Create(dir, backup.Sources{AppDir: appDir, ConfigDir: backup.ConfigDir(appDir)}, "update", time.Now())
+	if err != nil {
+		cl.logger.Printf("[WARNING] Backup failed: %v\n", err)
+		return
+	}
+	if path != "" {
+		cl.logger.Printf("Backup created: %s\n", path)
+	}
+	if _, err := backup.Prune(dir, cfg.BackupKeep); err != nil {
+		cl.logger.Printf("[WARNING] Could not remove old backups: %v\n", err)
+	}
 }
 
 // Check if Node.js is installed
@@ -249,18 +221,20 @@ func (cl *CloudLauncher) checkNodeJS() (string, error) {
 func (cl *CloudLauncher) installDependencies(appDir string) error {
 	cl.updateProgress(80, "Installiere Abhängigkeiten...")
 	
-	var cmd *exec.Cmd
-	if runtime.GOOS == "windows" {
-		cmd = exec.Command("cmd", "/C", "npm", "install", "--cache", "false")
-	} else {
-		cmd = exec.Command("npm", "install", "--cache", "false")
+	// Same npm cache as launcher.exe, reused after every update
+	cfg, err := config.Load(cl.baseDir)
+	if err != nil {
+		// Load returns the parsed values even when they fail validation
+		cl.logger.Printf("[WARNING] %v - using the npm cache from it anyway\n", err)
 	}
+	args := append([]string{"install"}, npmcache.Args(cfg.ResolveNpmCache(cl.baseDir))...)
+	cmd := procutil.NPM(args...)
 	
 	cmd.Dir = appDir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	
-	err := cmd.Run()
+	err = cmd.Run()
 	if err != nil {
 		return fmt.Errorf("Installation fehlgeschlagen: %v", err)
 	}
@@ -328,6 +302,19 @@ func (cl *CloudLauncher) run() error {
 		cl.logger.Printf("Failed to open browser: %v\n", err)
 	}
 	
+	// A full disk or a read-only folder would only show up as a broken
+	// extraction or an npm error later
+	appDir := filepath.Join(cl.baseDir, "app")
+	for _, p := range preflight.Check(appDir, backup.ConfigDir(appDir), true) {
+		if !p.Critical {
+			cl.logger.Printf("[WARNING] %s\n", p)
+			continue
+		}
+		cl.logger.Printf("[ERROR] %s\n", p)
+		cl.sendError(p.Message)
+		return errors.New(p.Message)
+	}
+	
 	// Download repository
 	if err := cl.downloadRepository(); err != nil {
 		cl.sendError(err.Error())
@@ -342,7 +329,6 @@ func (cl *CloudLauncher) run() error {
 	}
 	
 	// Install dependencies
-	appDir := filepath.Join(cl.baseDir, "app")
 	if err := cl.installDependencies(appDir); err != nil {
 		cl.sendError(err.Error())
 		return err